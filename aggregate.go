@@ -0,0 +1,299 @@
+package gomongo
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/globalsign/mgo/bson"
+	driverbson "go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/alishavirani/gomongo/internal/document"
+)
+
+// Pipeline is the list of stages of an aggregation, one operator per stage
+// The methods append a stage and return the new Pipeline, so a pipeline reads in the order it runs
+//
+//	pipeline := Pipeline{}.
+//		Match(bson.M{"status": "paid"}).
+//		Group("$customer", bson.M{"spent": bson.M{"$sum": "$total"}}).
+//		Sort("-spent").
+//		Limit(10)
+//
+// The sqlite, mysql and memory drivers run $match, $group, $sort, $project, $lookup, $unwind,
+// $facet, $skip, $limit and $count with field paths and literals as expressions
+type Pipeline []bson.M
+
+// Match : Function appends a $match stage, keeping the records matching query
+func (p Pipeline) Match(query bson.M) Pipeline {
+	return p.Stage(bson.M{"$match": query})
+}
+
+// Group : Function appends a $group stage
+// Input Parameters
+//		id (interface{}) : key of the groups, a field path like "$customer", a document of them, or nil for a single group
+//		fields (bson Object) : accumulators of the groups i.e, bson.M{"spent": bson.M{"$sum": "$total"}}
+func (p Pipeline) Group(id interface{}, fields bson.M) Pipeline {
+	group := bson.M{"_id": id}
+	for name, accumulator := range fields {
+		group[name] = accumulator
+	}
+	return p.Stage(bson.M{"$group": group})
+}
+
+// Sort : Function appends a $sort stage on fields in order, "-" sorts descending i.e, Sort("lastName", "-age")
+// An empty field is kept in the stage, so Aggregate fails with ErrorValidation rather than sorting on less fields
+func (p Pipeline) Sort(fields ...string) Pipeline {
+	keys := make([]document.SortKey, 0, len(fields))
+	for _, field := range fields {
+		parsed, err := sortKeys([]string{field})
+		if err != nil {
+			parsed = []document.SortKey{{}}
+		}
+		keys = append(keys, parsed...)
+	}
+	return p.Stage(bson.M{"$sort": sortDocument(keys)})
+}
+
+// Project : Function appends a $project stage, fields being included (1), excluded (0) or computed ("$path")
+func (p Pipeline) Project(fields bson.M) Pipeline {
+	return p.Stage(bson.M{"$project": fields})
+}
+
+// Lookup : Function appends a $lookup stage, which sets as to the records of from whose foreignField equals localField
+func (p Pipeline) Lookup(from, localField, foreignField, as string) Pipeline {
+	return p.Stage(bson.M{"$lookup": bson.M{"from": from, "localField": localField, "foreignField": foreignField, "as": as}})
+}
+
+// Unwind : Function appends an $unwind stage, which outputs a record per element of the array at path
+// Input Parameters
+//		path (string) : field of the array, with or without the leading $
+//		preserveEmpty (bool) : keep the records whose array is missing, null or empty
+func (p Pipeline) Unwind(path string, preserveEmpty bool) Pipeline {
+	if !strings.HasPrefix(path, "$") {
+		path = "$" + path
+	}
+	return p.Stage(bson.M{"$unwind": bson.M{"path": path, "preserveNullAndEmptyArrays": preserveEmpty}})
+}
+
+// Facet : Function appends a $facet stage, which runs every pipeline over the same records and outputs a
+// single record holding the results of each under its name
+func (p Pipeline) Facet(facets map[string]Pipeline) Pipeline {
+	spec := bson.M{}
+	for name, pipeline := range facets {
+		spec[name] = []bson.M(pipeline)
+	}
+	return p.Stage(bson.M{"$facet": spec})
+}
+
+// Skip : Function appends a $skip stage
+func (p Pipeline) Skip(n int) Pipeline {
+	return p.Stage(bson.M{"$skip": n})
+}
+
+// Limit : Function appends a $limit stage
+func (p Pipeline) Limit(n int) Pipeline {
+	return p.Stage(bson.M{"$limit": n})
+}
+
+// Stage : Function appends any stage, for the operators without a method of their own
+func (p Pipeline) Stage(stage bson.M) Pipeline {
+	//never share the array of p, two pipelines built from the same prefix stay apart
+	return append(p[:len(p):len(p)], stage)
+}
+
+// Aggregate : Function runs the aggregation pipeline over the collection and returns every record it outputs
+// Input Parameters
+//		*AggregateStruct (Struct) :
+// 			Pipeline(Pipeline) : stages of the aggregation
+//			AllowDiskUse(bool) : let the large stages use temporary files
+//			BatchSize(int), MaxTime(time.Duration) : tuning of the query
+// Output Parameters
+// 		records([]interface{}) : the records out of the last stage
+// 		error : if it was error then return error else nil
+func (c *Collection) Aggregate(aggregateStruct *AggregateStruct) ([]interface{}, error) {
+	return c.AggregateCtx(context.Background(), aggregateStruct)
+}
+
+// AggregateCtx : Function runs the aggregation pipeline over the collection, aborting when ctx is done
+// Input Parameters
+//		ctx (context.Context) : cancellation and deadline for the operation
+//		*AggregateStruct (Struct) :
+// 			Pipeline(Pipeline) : stages of the aggregation
+//			AllowDiskUse(bool) : let the large stages use temporary files
+//			BatchSize(int), MaxTime(time.Duration) : tuning of the query
+// Output Parameters
+// 		records([]interface{}) : the records out of the last stage
+// 		error : if it was error then return error else nil
+func (c *Collection) AggregateCtx(ctx context.Context, aggregateStruct *AggregateStruct) ([]interface{}, error) {
+	var records []interface{}
+	err := aggregateStruct.validate()
+	if err == nil {
+		err = c.exec(ctx, func(collection *mongo.Collection) error {
+			cursor, err := aggregate(ctx, collection, aggregateStruct)
+			if err != nil {
+				return err
+			}
+			return decodeAll(ctx, cursor, &records)
+		}, func(store Store) error {
+			return storeAggregate(ctx, store, c.Name, aggregateStruct.Pipeline, &records)
+		})
+	}
+	if err != nil {
+		log.Println(err)
+		return nil, newError("Aggregate", c.Name, err)
+	}
+	return records, nil
+}
+
+// AggregateAsync : Function runs the aggregation pipeline over the collection
+// Input Parameters
+//		*AggregateStruct (Struct) :
+// 			Pipeline(Pipeline) : stages of the aggregation
+//			callback (channel) : which returns data to goroutine
+// Output Parameters
+// 		callback(data, error) : returns the records, error to channel
+func (c *Collection) AggregateAsync(aggregateStruct *AggregateStruct, callback chan *Callback) {
+	records, err := c.Aggregate(aggregateStruct)
+	cb := new(Callback)
+	cb.Data = records
+	cb.Error = err
+	callback <- cb
+}
+
+// AggregateCursor : Function returns a Cursor over the records output by the aggregation pipeline, see FindCursor
+// Input Parameters
+//		*AggregateStruct (Struct) :
+// 			Pipeline(Pipeline) : stages of the aggregation
+//			BatchSize(int) : records per round trip
+// Output Parameters
+// 		*Cursor : cursor positioned before the first record
+// 		error : if it was error then return error else nil
+func (c *Collection) AggregateCursor(aggregateStruct *AggregateStruct) (*Cursor, error) {
+	return c.AggregateCursorCtx(context.Background(), aggregateStruct)
+}
+
+// AggregateCursorCtx : Function returns a Cursor over the records output by the aggregation pipeline, reading them with ctx
+// Input Parameters
+//		ctx (context.Context) : cancellation and deadline for the aggregation and the reads of the cursor
+//		*AggregateStruct (Struct) :
+// 			Pipeline(Pipeline) : stages of the aggregation
+//			BatchSize(int) : records per round trip
+// Output Parameters
+// 		*Cursor : cursor positioned before the first record
+// 		error : if it was error then return error else nil
+func (c *Collection) AggregateCursorCtx(ctx context.Context, aggregateStruct *AggregateStruct) (*Cursor, error) {
+	cursor := &Cursor{ctx: ctx, collection: c.Name}
+	err := aggregateStruct.validate()
+	if err == nil {
+		err = c.exec(ctx, func(collection *mongo.Collection) error {
+			var err error
+			cursor.cursor, err = aggregate(ctx, collection, aggregateStruct)
+			return err
+		}, func(store Store) error {
+			return storeAggregate(ctx, store, c.Name, aggregateStruct.Pipeline, &cursor.records)
+		})
+	}
+	if err != nil {
+		log.Println(err)
+		return nil, newError("AggregateCursor", c.Name, err)
+	}
+	return cursor, nil
+}
+
+// AggregateStream : Function sends the records output by the aggregation pipeline on callback one at a time, see FindStream
+// Input Parameters
+//		ctx (context.Context) : cancellation and deadline for the whole stream
+//		*AggregateStruct (Struct) :
+// 			Pipeline(Pipeline) : stages of the aggregation
+//		callback (channel) : which returns data to goroutine
+// Output Parameters
+// 		callback(data, error) : sends every record, then the error if there is one, to channel
+func (c *Collection) AggregateStream(ctx context.Context, aggregateStruct *AggregateStruct, callback chan *Callback) {
+	stream(ctx, func() (*Cursor, error) {
+		return c.AggregateCursorCtx(ctx, aggregateStruct)
+	}, callback)
+}
+
+// validate : Function checks the values of aggregateStruct which the server would reject
+func (aggregateStruct *AggregateStruct) validate() error {
+	switch {
+	case aggregateStruct.BatchSize < 0:
+		return fmt.Errorf("%w: BatchSize can't be negative", ErrorValidation)
+	case aggregateStruct.MaxTime < 0:
+		return fmt.Errorf("%w: MaxTime can't be negative", ErrorValidation)
+	}
+	for i, stage := range aggregateStruct.Pipeline {
+		if len(stage) != 1 {
+			return fmt.Errorf("%w: stage %d of the pipeline has %d operators instead of one", ErrorValidation, i, len(stage))
+		}
+		if spec, ok := stage["$sort"]; ok {
+			if err := validateSort(spec); err != nil {
+				return fmt.Errorf("%w: stage %d of the pipeline: %v", ErrorValidation, i, err)
+			}
+		}
+		//the pipelines of a $facet are checked the same way
+		facets, _ := stage["$facet"].(bson.M)
+		for _, facet := range facets {
+			if pipeline, ok := facet.([]bson.M); ok {
+				if err := (&AggregateStruct{Pipeline: pipeline}).validate(); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// validateSort : Function checks the document of a $sort stage has fields, none of them empty
+func validateSort(spec interface{}) error {
+	var fields []string
+	switch spec := spec.(type) {
+	case bson.D:
+		for _, elem := range spec {
+			fields = append(fields, elem.Name)
+		}
+	case bson.M:
+		for field := range spec {
+			fields = append(fields, field)
+		}
+	default:
+		return fmt.Errorf("$sort needs a document")
+	}
+	if len(fields) == 0 {
+		return fmt.Errorf("$sort needs at least one field")
+	}
+	for _, field := range fields {
+		if len(strings.TrimSpace(field)) == 0 {
+			return fmt.Errorf("empty field in $sort")
+		}
+	}
+	return nil
+}
+
+// aggregate : Function runs the pipeline of aggregateStruct on a MongoDB collection
+func aggregate(ctx context.Context, collection *mongo.Collection, aggregateStruct *AggregateStruct) (*mongo.Cursor, error) {
+	pipeline := make([]driverbson.Raw, len(aggregateStruct.Pipeline))
+	for i, stage := range aggregateStruct.Pipeline {
+		var err error
+		if pipeline[i], err = marshal(stage); err != nil {
+			return nil, err
+		}
+	}
+	opts := options.Aggregate()
+	if aggregateStruct.AllowDiskUse {
+		opts.SetAllowDiskUse(true)
+	}
+	if aggregateStruct.BatchSize > 0 {
+		opts.SetBatchSize(int32(aggregateStruct.BatchSize))
+	}
+	if aggregateStruct.MaxTime > 0 {
+		opts.SetMaxTime(aggregateStruct.MaxTime)
+	} else if timeout, ok := maxTime(ctx); ok {
+		opts.SetMaxTime(timeout)
+	}
+	return collection.Aggregate(ctx, pipeline, opts)
+}
@@ -0,0 +1,56 @@
+package gomongo
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/globalsign/mgo/bson"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPipeline(t *testing.T) {
+	pipeline := Pipeline{}.
+		Match(bson.M{"status": "paid"}).
+		Lookup("customers", "customer", "_id", "buyer").
+		Unwind("buyer", true).
+		Group("$buyer.country", bson.M{"spent": bson.M{"$sum": "$total"}}).
+		Sort("-spent", "_id").
+		Project(bson.M{"spent": 1}).
+		Skip(5).
+		Limit(10)
+	assert.Equal(t, Pipeline{
+		{"$match": bson.M{"status": "paid"}},
+		{"$lookup": bson.M{"from": "customers", "localField": "customer", "foreignField": "_id", "as": "buyer"}},
+		{"$unwind": bson.M{"path": "$buyer", "preserveNullAndEmptyArrays": true}},
+		{"$group": bson.M{"_id": "$buyer.country", "spent": bson.M{"$sum": "$total"}}},
+		{"$sort": bson.D{{Name: "spent", Value: -1}, {Name: "_id", Value: 1}}},
+		{"$project": bson.M{"spent": 1}},
+		{"$skip": 5},
+		{"$limit": 10},
+	}, pipeline)
+
+	facet := Pipeline{}.Facet(map[string]Pipeline{"top": Pipeline{}.Limit(3)})
+	assert.Equal(t, Pipeline{{"$facet": bson.M{"top": []bson.M{{"$limit": 3}}}}}, facet)
+
+	//sharing a prefix doesn't let two pipelines overwrite each other
+	prefix := make(Pipeline, 0, 4).Match(bson.M{})
+	a, b := prefix.Limit(1), prefix.Skip(1)
+	assert.Equal(t, bson.M{"$limit": 1}, a[1])
+	assert.Equal(t, bson.M{"$skip": 1}, b[1])
+}
+
+func TestAggregateValidate(t *testing.T) {
+	assert.Nil(t, (&AggregateStruct{Pipeline: Pipeline{}.Limit(1)}).validate())
+	assert.True(t, errors.Is((&AggregateStruct{BatchSize: -1}).validate(), ErrorValidation))
+	assert.True(t, errors.Is((&AggregateStruct{Pipeline: Pipeline{{}}}).validate(), ErrorValidation))
+
+	//an empty sort field is kept in the stage and rejected, not dropped
+	sort := Pipeline{}.Sort("-spent", "")
+	assert.Equal(t, Pipeline{{"$sort": bson.D{{Name: "spent", Value: -1}, {Name: "", Value: 1}}}}, sort)
+	assert.True(t, errors.Is((&AggregateStruct{Pipeline: sort}).validate(), ErrorValidation))
+	assert.True(t, errors.Is((&AggregateStruct{Pipeline: Pipeline{}.Sort()}).validate(), ErrorValidation))
+	assert.True(t, errors.Is((&AggregateStruct{Pipeline: Pipeline{}.Stage(bson.M{"$sort": bson.M{}})}).validate(), ErrorValidation))
+	assert.Nil(t, (&AggregateStruct{Pipeline: Pipeline{}.Stage(bson.M{"$sort": bson.M{"spent": -1}})}).validate())
+	facet := Pipeline{}.Facet(map[string]Pipeline{"top": Pipeline{}.Sort("")})
+	assert.True(t, errors.Is((&AggregateStruct{Pipeline: facet}).validate(), ErrorValidation))
+}
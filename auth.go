@@ -0,0 +1,56 @@
+package gomongo
+
+import (
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// externalSource is the auth database of the mechanisms whose users live outside of MongoDB
+const externalSource = "$external"
+
+// validateCredentials : Function checks the credentials of info against its mechanism, so a misconfigured
+// connection fails before dialing instead of with an obscure authentication error from the server
+// An empty mechanism is negotiated with the server (SCRAM-SHA-256 or SCRAM-SHA-1)
+// Mechanisms whose users live outside of MongoDB get "$external" as auth database unless one is given
+func validateCredentials(info *options.Credential) error {
+	if info.AuthMechanism == "" {
+		if info.Username == "" && info.Password != "" {
+			return fmt.Errorf("%w: password given without username", ErrorInvalidConfig)
+		}
+		return nil
+	}
+
+	switch info.AuthMechanism {
+	case SCRAMSHA1, SCRAMSHA256, MONGODBCR:
+		if info.Username == "" || info.Password == "" {
+			return fmt.Errorf("%w: %s requires username and password", ErrorInvalidConfig, info.AuthMechanism)
+		}
+	case PLAIN:
+		if info.Username == "" || info.Password == "" {
+			return fmt.Errorf("%w: %s requires username and password", ErrorInvalidConfig, info.AuthMechanism)
+		}
+		if info.AuthSource == "" {
+			info.AuthSource = externalSource
+		}
+	case X509:
+		//without username the server takes the subject of the client certificate (MongoDB 3.4+)
+		if info.Password != "" {
+			return fmt.Errorf("%w: %s doesn't take a password", ErrorInvalidConfig, info.AuthMechanism)
+		}
+		if info.AuthSource != "" && info.AuthSource != externalSource {
+			return fmt.Errorf("%w: %s users must be authenticated against %s, not %s", ErrorInvalidConfig, info.AuthMechanism, externalSource, info.AuthSource)
+		}
+		info.AuthSource = externalSource
+	case GSSAPI:
+		if info.Username == "" {
+			return fmt.Errorf("%w: %s requires the Kerberos principal as username", ErrorInvalidConfig, info.AuthMechanism)
+		}
+		if info.AuthSource == "" {
+			info.AuthSource = externalSource
+		}
+	default:
+		return fmt.Errorf("%w: unknown authentication mechanism %q", ErrorInvalidConfig, info.AuthMechanism)
+	}
+	return nil
+}
@@ -0,0 +1,131 @@
+package gomongo
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/globalsign/mgo/bson"
+	driverbson "go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// The public API keeps using the bson package of mgo (bson.M, bson.ObjectId, `bson:"..."` tags),
+// values are encoded with it and handed over to the driver as raw BSON, and the raw BSON read by
+// the driver is decoded with it, so the records look the same whatever the driver.
+
+// emptyDocument is the raw BSON of {}
+var emptyDocument = driverbson.Raw{5, 0, 0, 0, 0}
+
+// marshal : Function encodes a document, query or update given by the caller into raw BSON, nil being {}
+func marshal(value interface{}) (driverbson.Raw, error) {
+	if value == nil {
+		return emptyDocument, nil
+	}
+	if m, ok := value.(bson.M); ok && m == nil {
+		return emptyDocument, nil
+	}
+	data, err := bson.Marshal(value)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrorValidation, err)
+	}
+	return driverbson.Raw(data), nil
+}
+
+// withID : Function encodes a document to insert, giving it a new ObjectId as first field when it has no _id
+// Output Parameters
+//		driverbson.Raw : the encoded document
+//		interface{} : its _id
+//		error : ErrorValidation if the document can't be encoded
+func withID(value interface{}) (driverbson.Raw, interface{}, error) {
+	raw, err := marshal(value)
+	if err != nil {
+		return nil, nil, err
+	}
+	var doc bson.D
+	if err := bson.Unmarshal(raw, &doc); err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", ErrorValidation, err)
+	}
+	for _, elem := range doc {
+		if elem.Name == "_id" {
+			return raw, elem.Value, nil
+		}
+	}
+	id := bson.NewObjectId()
+	raw, err = marshal(append(bson.D{{Name: "_id", Value: id}}, doc...))
+	return raw, id, err
+}
+
+// isReplacement : Function reports if an encoded update replaces the whole document,
+// i.e. its first key isn't an update operator like $set
+func isReplacement(update driverbson.Raw) bool {
+	elements, err := update.Elements()
+	if err != nil || len(elements) == 0 {
+		return true
+	}
+	return !strings.HasPrefix(elements[0].Key(), "$")
+}
+
+// updateOne : Function applies update to the first document matching filter, replacing it when update has no operators
+func updateOne(ctx context.Context, collection *mongo.Collection, filter interface{}, update interface{}, upsert bool) (*mongo.UpdateResult, error) {
+	filterDoc, err := marshal(filter)
+	if err != nil {
+		return nil, err
+	}
+	updateDoc, err := marshal(update)
+	if err != nil {
+		return nil, err
+	}
+	if isReplacement(updateDoc) {
+		return collection.ReplaceOne(ctx, filterDoc, updateDoc, options.Replace().SetUpsert(upsert))
+	}
+	return collection.UpdateOne(ctx, filterDoc, updateDoc, options.Update().SetUpsert(upsert))
+}
+
+// decode : Function decodes a raw record into result, which must be a pointer
+func decode(data []byte, result interface{}) error {
+	if err := bson.Unmarshal(data, result); err != nil {
+		return fmt.Errorf("%w: %v", ErrorValidation, err)
+	}
+	return nil
+}
+
+// decodeAll : Function decodes every record of cursor into result, which must be a pointer to a slice
+func decodeAll(ctx context.Context, cursor *mongo.Cursor, result interface{}) error {
+	defer cursor.Close(ctx)
+
+	slice := reflect.ValueOf(result).Elem()
+	records := reflect.MakeSlice(slice.Type(), 0, cursor.RemainingBatchLength())
+	for cursor.Next(ctx) {
+		record := reflect.New(slice.Type().Elem())
+		if err := decode(cursor.Current, record.Interface()); err != nil {
+			return err
+		}
+		records = reflect.Append(records, record.Elem())
+	}
+	if err := cursor.Err(); err != nil {
+		return err
+	}
+	slice.Set(records)
+	return nil
+}
+
+// fromDriver : Function converts a value decoded by the driver (i.e, an upserted _id) into its mgo bson form
+func fromDriver(value interface{}) interface{} {
+	if value == nil {
+		return nil
+	}
+	data, err := driverbson.Marshal(driverbson.D{{Key: "v", Value: value}})
+	if err != nil {
+		return value
+	}
+	var doc struct {
+		V interface{} `bson:"v"`
+	}
+	if err := bson.Unmarshal(data, &doc); err != nil {
+		return value
+	}
+	return doc.V
+}
@@ -0,0 +1,28 @@
+package gomongo
+
+import (
+	"testing"
+
+	"github.com/globalsign/mgo/bson"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithID(t *testing.T) {
+	raw, id, err := withID(struct {
+		Name string `bson:"name"`
+		Age  int    `bson:"age"`
+	}{"Amulya", 26})
+	assert.Nil(t, err)
+	objectId, ok := id.(bson.ObjectId)
+	assert.True(t, ok && objectId.Valid())
+	var doc bson.D
+	assert.Nil(t, bson.Unmarshal(raw, &doc))
+	assert.Equal(t, bson.D{{Name: "_id", Value: objectId}, {Name: "name", Value: "Amulya"}, {Name: "age", Value: 26}}, doc, "the fields keep their order")
+
+	_, id, err = withID(bson.M{"_id": "amulya", "age": 26})
+	assert.Nil(t, err)
+	assert.Equal(t, "amulya", id)
+
+	_, _, err = withID(42)
+	assert.ErrorIs(t, err, ErrorValidation)
+}
@@ -0,0 +1,850 @@
+package gomongo
+
+import (
+	"context"
+	"log"
+
+	"github.com/globalsign/mgo/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Collection is a handle on one collection of a Connection
+// It carries its own collection name, so unlike the Connection level operations
+// (which read the mutable Connection.Collection field) a single Connection can be
+// shared by goroutines working on different collections
+type Collection struct {
+	Name string      //collection name
+	conn *Connection //connection the handle belongs to
+}
+
+// C : Function returns the handle for the named collection
+// Handles are cached in Connection.Collections, so asking twice for the same name returns the same handle
+// Input Parameters
+//		name(string) : collection name
+// Output Parameters
+//		*Collection : handle exposing all the operations of the collection
+func (conn *Connection) C(name string) *Collection {
+	conn.collectionsMu.Lock()
+	defer conn.collectionsMu.Unlock()
+
+	if conn.Collections == nil {
+		conn.Collections = make(map[string]*Collection)
+	}
+	c, ok := conn.Collections[name]
+	if !ok {
+		c = &Collection{Name: name, conn: conn}
+		conn.Collections[name] = c
+	}
+	return c
+}
+
+// BulkInsert : Function inserts the data in bulk to the collection
+// Input Parameters :
+// 		*BulkInsertStruct (Struct) :
+//	 		Data([] interfaces{}]) : the object which has to be inserted
+// Output Parameters
+// 		result(*WriteResult) : returns the _id of the inserted records
+// 		error : if it was error then return error else nil
+func (c *Collection) BulkInsert(bulkInsertStruct *BulkInsertStruct) (*WriteResult, error) {
+	return c.BulkInsertCtx(context.Background(), bulkInsertStruct)
+}
+
+// BulkInsertCtx : Function inserts the data in bulk to the collection, aborting when ctx is done
+// Input Parameters :
+// 		ctx (context.Context) : cancellation and deadline for the operation
+// 		*BulkInsertStruct (Struct) :
+//	 		Data([] interfaces{}]) : the object which has to be inserted
+// Output Parameters
+// 		result(*WriteResult) : returns the _id of the inserted records
+// 		error : if it was error then return error else nil
+func (c *Collection) BulkInsertCtx(ctx context.Context, bulkInsertStruct *BulkInsertStruct) (*WriteResult, error) {
+	result := new(WriteResult)
+	err := c.exec(ctx, func(collection *mongo.Collection) error {
+		docs := make([]interface{}, len(bulkInsertStruct.Data))
+		ids := make([]interface{}, len(bulkInsertStruct.Data))
+		for i, data := range bulkInsertStruct.Data {
+			doc, id, err := withID(data)
+			if err != nil {
+				return err
+			}
+			docs[i], ids[i] = doc, id
+		}
+		if len(docs) == 0 {
+			return nil
+		}
+		if _, err := collection.InsertMany(ctx, docs, options.InsertMany().SetOrdered(false)); err != nil {
+			return err
+		}
+		result.InsertedIds = ids
+		return nil
+	}, func(store Store) error {
+		docs, err := toDocuments(bulkInsertStruct.Data)
+		if err != nil {
+			return err
+		}
+		if err := store.Insert(ctx, c.Name, docs); err != nil {
+			return err
+		}
+		result.InsertedIds = insertedIds(docs)
+		return nil
+	})
+	if err != nil {
+		log.Println(err)
+		return nil, newError("BulkInsert", c.Name, err)
+	}
+	return result, nil
+}
+
+// Insert : Function inserts the data object into the collection
+// Input Parameters :
+// 		*InsertStruct (Struct) :
+//	 		Data([] interfaces{}]) : the object which has to be inserted
+// Output Parameters
+// 		result(*WriteResult) : returns the _id of the record, the ObjectId given to it when it had none
+// 		error : if it was error then return error else nil
+func (c *Collection) Insert(insertStruct *InsertStruct) (*WriteResult, error) {
+	return c.InsertCtx(context.Background(), insertStruct)
+}
+
+// InsertCtx : Function inserts the data object into the collection, aborting when ctx is done
+// Input Parameters :
+// 		ctx (context.Context) : cancellation and deadline for the operation
+// 		*InsertStruct (Struct) :
+//	 		Data([] interfaces{}]) : the object which has to be inserted
+// Output Parameters
+// 		result(*WriteResult) : returns the _id of the record, the ObjectId given to it when it had none
+// 		error : if it was error then return error else nil
+func (c *Collection) InsertCtx(ctx context.Context, insertStruct *InsertStruct) (*WriteResult, error) {
+	result := new(WriteResult)
+	err := c.exec(ctx, func(collection *mongo.Collection) error {
+		doc, id, err := withID(insertStruct.Data)
+		if err != nil {
+			return err
+		}
+		if _, err := collection.InsertOne(ctx, doc); err != nil {
+			return err
+		}
+		result.InsertedIds = []interface{}{id}
+		return nil
+	}, func(store Store) error {
+		docs, err := toDocuments([]interface{}{insertStruct.Data})
+		if err != nil {
+			return err
+		}
+		if err := store.Insert(ctx, c.Name, docs); err != nil {
+			return err
+		}
+		result.InsertedIds = insertedIds(docs)
+		return nil
+	})
+	if err != nil {
+		log.Println(err)
+		return nil, newError("Insert", c.Name, err)
+	}
+	return result, nil
+}
+
+// InsertAsync : Function inserts the data object into the collection
+// Input Parameters :
+// 		*InsertStruct (Struct) :
+//	 		Data([] interfaces{}]) : the object which has to be inserted
+//			callback (channel) : which returns data to goroutine
+// Output Parameters
+// 		callback(data, error) : sends the *WriteResult, error back to channel
+func (c *Collection) InsertAsync(insertStruct *InsertStruct, callback chan *Callback) {
+	result, err := c.Insert(insertStruct)
+	cb := new(Callback)
+	cb.Data = result
+	cb.Error = err
+	callback <- cb
+}
+
+// Update : Function Updates the record into the collection
+// Input Parameters :
+// 		*UpdateStruct (Struct) :
+//	 		Data([] interfaces{}]) : the object which has to be inserted
+// 			Id(interface{}) : The record id (Hexadecimal ObjectId, StringID, int, UUID, etc) whose details have to be updated
+// Output Parameters
+// 		result(*WriteResult) : returns matched, modified counts
+// 		error : ErrorNotFound if no record matched, else error if it failed, nil on success
+
+func (c *Collection) Update(updateStruct *UpdateStruct) (*WriteResult, error) {
+	return c.UpdateCtx(context.Background(), updateStruct)
+}
+
+// UpdateCtx : Function Updates the record into the collection, aborting when ctx is done
+// Input Parameters :
+// 		ctx (context.Context) : cancellation and deadline for the operation
+// 		*UpdateStruct (Struct) :
+//	 		Data([] interfaces{}]) : the object which has to be inserted
+// 			Id(interface{}) : The record id (Hexadecimal ObjectId, StringID, int, UUID, etc) whose details have to be updated
+// Output Parameters
+// 		result(*WriteResult) : returns matched, modified counts
+// 		error : ErrorNotFound if no record matched, else error if it failed, nil on success
+func (c *Collection) UpdateCtx(ctx context.Context, updateStruct *UpdateStruct) (*WriteResult, error) {
+	var result *WriteResult
+	id, err := normalizeID(updateStruct.Id)
+	if err == nil {
+		err = c.exec(ctx, func(collection *mongo.Collection) error {
+			updated, err := updateOne(ctx, collection, bson.M{"_id": id}, updateStruct.Data, false)
+			result = writeResult(updated)
+			return err
+		}, func(store Store) error {
+			var err error
+			result, err = storeUpdate(ctx, store, c.Name, bson.M{"_id": id}, updateStruct.Data, false, false)
+			return err
+		})
+	}
+	if err == nil && result.Matched == 0 {
+		err = ErrorNotFound
+	}
+	if err != nil {
+		log.Println(err)
+		return nil, newError("Update", c.Name, err)
+	}
+	return result, nil
+}
+
+// UpdateAsync : Function Updates the record into the collection
+// Input Parameters
+// 		*UpdateStruct (Struct) :
+//	 		Data([] interfaces{}]) : the object which has to be inserted
+// 			Id(interface{}) : The record id (Hexadecimal ObjectId, StringID, int, UUID, etc) whose details have to be updated
+//			callback (channel) : which returns data to goroutine
+// Output Parameters
+// 		callback(data, error) : sends the *WriteResult, error back to channel
+func (c *Collection) UpdateAsync(updateStruct *UpdateStruct, callback chan *Callback) {
+	result, err := c.Update(updateStruct)
+	cb := new(Callback)
+	cb.Data = result
+	cb.Error = err
+	callback <- cb
+}
+
+// Upsert : Function Updates the record if found else inserts as a new record into the collection
+// Input Parameters :
+// 		*UpsertStruct (Struct) :
+//	 		Data([] interfaces{}]) : the object which has to be inserted
+// 			Id(interface{}) : The record id (Hexadecimal ObjectId, StringID, int, UUID, etc) whose details have to be updated
+// Output Parameters
+// 		info(*WriteResult) : returns matched, modified counts and the _id of the inserted record
+// 		error : if it was error then return error else nil
+
+func (c *Collection) Upsert(upsertStruct *UpsertStruct) (*WriteResult, error) {
+	return c.UpsertCtx(context.Background(), upsertStruct)
+}
+
+// UpsertCtx : Function Updates the record if found else inserts as a new record, aborting when ctx is done
+// Input Parameters :
+// 		ctx (context.Context) : cancellation and deadline for the operation
+// 		*UpsertStruct (Struct) :
+//	 		Data([] interfaces{}]) : the object which has to be inserted
+// 			Id(interface{}) : The record id (Hexadecimal ObjectId, StringID, int, UUID, etc) whose details have to be updated
+// Output Parameters
+// 		info(*WriteResult) : returns matched, modified counts and the _id of the inserted record
+// 		error : if it was error then return error else nil
+func (c *Collection) UpsertCtx(ctx context.Context, upsertStruct *UpsertStruct) (*WriteResult, error) {
+	var info *WriteResult
+	id, err := normalizeID(upsertStruct.Id)
+	if err == nil {
+		err = c.exec(ctx, func(collection *mongo.Collection) error {
+			result, err := updateOne(ctx, collection, bson.M{"_id": id}, upsertStruct.Data, true)
+			info = writeResult(result)
+			return err
+		}, func(store Store) error {
+			var err error
+			info, err = storeUpdate(ctx, store, c.Name, bson.M{"_id": id}, upsertStruct.Data, false, true)
+			return err
+		})
+	}
+	if err != nil {
+		log.Println(err)
+		return nil, newError("Upsert", c.Name, err)
+	}
+	return info, nil
+}
+
+// UpsertAsync : Function Updates the record if found else inserts as a new record into the collection
+// Input Parameters :
+// 		*UpsertStruct (Struct) :
+//	 		Data([] interfaces{}]) : the object which has to be inserted
+// 			Id(interface{}) : The record id (Hexadecimal ObjectId, StringID, int, UUID, etc) whose details have to be updated
+// Output Parameters
+// 		callback(data, error) : sends updated, matched, modified counts back to channel
+
+func (c *Collection) UpsertAsync(upsertStruct *UpsertStruct, callback chan *Callback) {
+
+	info, err := c.Upsert(upsertStruct)
+	cb := new(Callback)
+	cb.Data = info
+	cb.Error = err
+	callback <- cb
+}
+
+// UpdateOne : Function Updates the matching record into the collection
+// Input Parameters
+// 		updateOneStruct (Struct) :
+//	 		Data(interfaces{}]) : the object which has to be inserted
+// 			Query(bson Object) : Criteria as per the update should execute
+// Output Parameters
+// 		result(*WriteResult) : returns matched, modified counts
+// 		error : ErrorNotFound if no record matched, else error if it failed, nil on success
+
+func (c *Collection) UpdateOne(updateOneStruct UpdateOneStruct) (*WriteResult, error) {
+	return c.UpdateOneCtx(context.Background(), updateOneStruct)
+}
+
+// UpdateOneCtx : Function Updates the matching record into the collection, aborting when ctx is done
+// Input Parameters
+// 		ctx (context.Context) : cancellation and deadline for the operation
+// 		updateOneStruct (Struct) :
+//	 		Data(interfaces{}]) : the object which has to be inserted
+// 			Query(bson Object) : Criteria as per the update should execute
+// Output Parameters
+// 		result(*WriteResult) : returns matched, modified counts
+// 		error : ErrorNotFound if no record matched, else error if it failed, nil on success
+func (c *Collection) UpdateOneCtx(ctx context.Context, updateOneStruct UpdateOneStruct) (*WriteResult, error) {
+	var result *WriteResult
+	err := c.exec(ctx, func(collection *mongo.Collection) error {
+		updated, err := updateOne(ctx, collection, updateOneStruct.Query, updateOneStruct.Data, false)
+		result = writeResult(updated)
+		return err
+	}, func(store Store) error {
+		var err error
+		result, err = storeUpdate(ctx, store, c.Name, updateOneStruct.Query, updateOneStruct.Data, false, false)
+		return err
+	})
+	if err == nil && result.Matched == 0 {
+		err = ErrorNotFound
+	}
+	if err != nil {
+		log.Println(err)
+		return nil, newError("UpdateOne", c.Name, err)
+	}
+	return result, nil
+}
+
+// UpdateAll : Function Updates all the record into the collection
+// Input Parameters
+// 		UpdateAllStruct (Struct) :
+//	 		Data([] interfaces{}]) : the object which has to be inserted
+// 			Query(bson Object) : Criteria as per the update should execute
+// Output Parameters
+// 		records(*WriteResult) : returns matched, modified counts
+// 		error : if it was error then return error else nil
+
+func (c *Collection) UpdateAll(updateAllStruct UpdateAllStruct) (*WriteResult, error) {
+	return c.UpdateAllCtx(context.Background(), updateAllStruct)
+}
+
+// UpdateAllCtx : Function Updates all the record into the collection, aborting when ctx is done
+// Input Parameters
+// 		ctx (context.Context) : cancellation and deadline for the operation
+// 		UpdateAllStruct (Struct) :
+//	 		Data([] interfaces{}]) : the object which has to be inserted
+// 			Query(bson Object) : Criteria as per the update should execute
+// Output Parameters
+// 		records(*WriteResult) : returns matched, modified counts
+// 		error : if it was error then return error else nil
+func (c *Collection) UpdateAllCtx(ctx context.Context, updateAllStruct UpdateAllStruct) (*WriteResult, error) {
+	var records *WriteResult
+	err := c.exec(ctx, func(collection *mongo.Collection) error {
+		filter, err := marshal(updateAllStruct.Query)
+		if err != nil {
+			return err
+		}
+		update, err := marshal(updateAllStruct.Data)
+		if err != nil {
+			return err
+		}
+		result, err := collection.UpdateMany(ctx, filter, update)
+		records = writeResult(result)
+		return err
+	}, func(store Store) error {
+		var err error
+		records, err = storeUpdate(ctx, store, c.Name, updateAllStruct.Query, updateAllStruct.Data, true, false)
+		return err
+	})
+	if err != nil {
+		log.Println(err)
+		return nil, newError("UpdateAll", c.Name, err)
+	}
+	return records, nil
+}
+
+// UpdateAllAsync : Function Updates all the record into the collection
+// Input Parameters
+// 		UpdateAllStruct (Struct) :
+// 			Query(bson Object) : Criteria as per the update should execute
+//	 		Data([] interfaces{}]) : the object which has to be inserted
+//			callback (channel) : which returns data to goroutine
+// Output Parameters
+// 		callback(data, error) : sends updated, matched, modified counts back to channel
+
+func (c *Collection) UpdateAllAsync(updateAllStruct UpdateAllStruct, callback chan *Callback) {
+	records, err := c.UpdateAll(updateAllStruct)
+	cb := new(Callback)
+	cb.Data = records
+	cb.Error = err
+	callback <- cb
+}
+
+// UpsertAll : Function Upserts record into the collection if not found else updates
+// Input Parameters
+//	*UpsertAllStruct (Struct) :
+//		Data([] interfaces{}]) : the object which has to be inserted
+//		Query(bson Object) : Criteria as per the update should execute
+// Output Parameters
+//	records(*WriteResult) : returns matched, modified counts
+//	error : if it was error then return error else nil
+
+func (c *Collection) UpsertAll(upsertAllStruct *UpsertAllStruct) (*WriteResult, error) {
+	return c.UpsertAllCtx(context.Background(), upsertAllStruct)
+}
+
+// UpsertAllCtx : Function Upserts record into the collection if not found else updates, aborting when ctx is done
+// Input Parameters
+//	ctx (context.Context) : cancellation and deadline for the operation
+//	*UpsertAllStruct (Struct) :
+//		Data([] interfaces{}]) : the object which has to be inserted
+//		Query(bson Object) : Criteria as per the update should execute
+// Output Parameters
+//	records(*WriteResult) : returns matched, modified counts
+//	error : if it was error then return error else nil
+func (c *Collection) UpsertAllCtx(ctx context.Context, upsertAllStruct *UpsertAllStruct) (*WriteResult, error) {
+	var records *WriteResult
+	err := c.exec(ctx, func(collection *mongo.Collection) error {
+		result, err := updateOne(ctx, collection, upsertAllStruct.Query, upsertAllStruct.Data, true)
+		records = writeResult(result)
+		return err
+	}, func(store Store) error {
+		var err error
+		records, err = storeUpdate(ctx, store, c.Name, upsertAllStruct.Query, upsertAllStruct.Data, false, true)
+		return err
+	})
+	if err != nil {
+		log.Println(err)
+		return nil, newError("UpsertAll", c.Name, err)
+	}
+	return records, nil
+}
+
+// UpsertAllAsync : Function Upserts record into the collection if not found else updates
+// Input Parameters
+//	*UpsertAllStruct (Struct) :
+//		Data([] interfaces{}]) : the object which has to be inserted
+//		Query(bson Object) : Criteria as per the update should execute
+// Output Parameters
+//	callback(data, error) : sends data, error to channel
+
+func (c *Collection) UpsertAllAsync(upsertAllStruct *UpsertAllStruct, callback chan *Callback) {
+	records, err := c.UpsertAll(upsertAllStruct)
+	cb := new(Callback)
+	cb.Data = records
+	cb.Error = err
+	callback <- cb
+}
+
+//	FindByID : Function FindByID finds and returns record by ID
+//	Input Parameters :
+// 		*FindByIDStruct (Struct) :
+// 			Id(interface{}) : The record id (Hexadecimal ObjectId, StringID, int, UUID, etc) whose details have to be updated
+//	Output :
+//		record(interface{}) : Returns the mongo Object
+//		error : ErrorNotFound if there is no record with the ID, else error if it failed
+func (c *Collection) FindByID(findByIDStruct *FindByIDStruct) (interface{}, error) {
+	return c.FindByIDCtx(context.Background(), findByIDStruct)
+}
+
+//	FindByIDCtx : Function finds and returns record by ID, aborting when ctx is done
+//	Input Parameters :
+// 		ctx (context.Context) : cancellation and deadline for the operation
+// 		*FindByIDStruct (Struct) :
+// 			Id(interface{}) : The record id (Hexadecimal ObjectId, StringID, int, UUID, etc) whose details have to be updated
+//	Output :
+//		record(interface{}) : Returns the mongo Object
+//		error : ErrorNotFound if there is no record with the ID, else error if it failed
+func (c *Collection) FindByIDCtx(ctx context.Context, findByIDStruct *FindByIDStruct) (interface{}, error) {
+	var record interface{}
+	err := c.findByID(ctx, findByIDStruct, &record)
+	if err != nil {
+		log.Println(err)
+		return nil, newError("FindByID", c.Name, err)
+	}
+	return record, nil
+}
+
+//	FindByIDAsync : Function FindByIDAsync finds and returns record by ID
+//	Input Parameters :
+// 		*FindByIDStruct (Struct) :
+// 			Id(interface{}) : The record id (Hexadecimal ObjectId, StringID, int, UUID, etc) whose details have to be updated
+//			callback (channel) : which returns data to goroutine
+//	Output :
+//		callback(data, error) : sends data, error to channel
+func (c *Collection) FindByIDAsync(findByIDStruct *FindByIDStruct, callback chan *Callback) {
+	records, err := c.FindByID(findByIDStruct)
+	cb := new(Callback)
+	cb.Data = records
+	cb.Error = err
+	callback <- cb
+}
+
+// Find : Function finds the record into the collection according to the query/criteria
+// Input Parameters
+//		*FindStruct (Struct) :
+// 			Query(bson Object) : Criteria as per the update should execute
+//			Options(map[string]int) : optional things like, limit, skip, etc
+// Output Parameters
+// 		records([]interface{]}) : Return the result mapped as interface
+// 		error(error) : if it was error then return error else nil
+func (c *Collection) Find(findStruct *FindStruct) ([]interface{}, error) {
+	return c.FindCtx(context.Background(), findStruct)
+}
+
+// FindCtx : Function finds the record into the collection according to the query/criteria, aborting when ctx is done
+// Input Parameters
+//		ctx (context.Context) : cancellation and deadline for the operation
+//		*FindStruct (Struct) :
+// 			Query(bson Object) : Criteria as per the update should execute
+//			Options(map[string]int) : optional things like, limit, skip, etc
+// Output Parameters
+// 		records([]interface{]}) : Return the result mapped as interface
+// 		error(error) : if it was error then return error else nil
+func (c *Collection) FindCtx(ctx context.Context, findStruct *FindStruct) ([]interface{}, error) {
+
+	var records []interface{}
+
+	err := c.find(ctx, findStruct, &records)
+	if err != nil {
+		log.Println(err)
+		return nil, newError("Find", c.Name, err)
+	}
+
+	return records, nil
+}
+
+// FindAsync : Function finds the record into the collection according to the query/criteria
+// Input Parameters
+//		*FindStruct (Struct) :
+// 			Query(bson Object) : Criteria as per the update should execute
+//			Options(map[string]int) : optional things like, limit, skip, etc
+//			callback (channel) : which returns data to goroutine
+// Output Parameters
+// 		callback(data, error) : returns data, error to channel
+func (c *Collection) FindAsync(findStruct *FindStruct, callback chan *Callback) {
+	records, err := c.Find(findStruct)
+	cb := new(Callback)
+	cb.Data = records
+	cb.Error = err
+	callback <- cb
+}
+
+// FindAll : Function finds all the records into the collection
+// Input Parameters
+//		*FindAllStruct (Struct) :
+// Output Parameters
+// 		records([]interface{}) : all the records present in database
+// 		error : if it was error then return error else nil
+func (c *Collection) FindAll(findAllStruct *FindAllStruct) ([]interface{}, error) {
+	return c.FindAllCtx(context.Background(), findAllStruct)
+}
+
+// FindAllCtx : Function finds all the records into the collection, aborting when ctx is done
+// Input Parameters
+//		ctx (context.Context) : cancellation and deadline for the operation
+//		*FindAllStruct (Struct) :
+// Output Parameters
+// 		records([]interface{}) : all the records present in database
+// 		error : if it was error then return error else nil
+func (c *Collection) FindAllCtx(ctx context.Context, findAllStruct *FindAllStruct) ([]interface{}, error) {
+
+	var records []interface{}
+
+	err := c.findAll(ctx, findAllStruct, &records)
+	if err != nil {
+		log.Println(err)
+		return nil, newError("FindAll", c.Name, err)
+	}
+
+	return records, nil
+}
+
+// FindAllAsync : Function finds all the records into the collection
+// Input Parameters
+//		*FindAllStruct (Struct) :
+//			callback (channel) : which returns data to goroutine
+// Output Parameters
+// 		callback(data, error) : returns data, error to channel
+func (c *Collection) FindAllAsync(findAllStruct *FindAllStruct, callback chan *Callback) {
+	records, err := c.FindAll(findAllStruct)
+	cb := new(Callback)
+	cb.Data = records
+	cb.Error = err
+	callback <- cb
+}
+
+// Remove : Function removes the record from the collection as per criteria/query
+// Input Parameters
+//		*RemoveStruct (Struct) :
+// 			Query(bson Object) : Criteria as per the update should execute
+// Output Parameters
+// 		result(*WriteResult) : returns matched, removed counts
+// 		error : ErrorNotFound if no record matched, else error if it failed, nil on success
+func (c *Collection) Remove(removeStruct *RemoveStruct) (*WriteResult, error) {
+	return c.RemoveCtx(context.Background(), removeStruct)
+}
+
+// RemoveCtx : Function removes the record from the collection as per criteria/query, aborting when ctx is done
+// Input Parameters
+//		ctx (context.Context) : cancellation and deadline for the operation
+//		*RemoveStruct (Struct) :
+// 			Query(bson Object) : Criteria as per the update should execute
+// Output Parameters
+// 		result(*WriteResult) : returns matched, removed counts
+// 		error : ErrorNotFound if no record matched, else error if it failed, nil on success
+func (c *Collection) RemoveCtx(ctx context.Context, removeStruct *RemoveStruct) (*WriteResult, error) {
+	var result *WriteResult
+	err := c.exec(ctx, func(collection *mongo.Collection) error {
+		filter, err := marshal(removeStruct.Query)
+		if err != nil {
+			return err
+		}
+		removed, err := collection.DeleteOne(ctx, filter)
+		result = removeResult(removed)
+		return err
+	}, func(store Store) error {
+		query, err := toDocument(removeStruct.Query)
+		if err != nil {
+			return err
+		}
+		result, err = store.Remove(ctx, c.Name, query, false)
+		return err
+	})
+	if err == nil && result.Removed == 0 {
+		err = ErrorNotFound
+	}
+	if err != nil {
+		log.Println(err)
+		return nil, newError("Remove", c.Name, err)
+	}
+	return result, nil
+}
+
+// removeAsync : Function removes the record from the collection as per criteria/query
+// Input Parameters
+//		*RemoveStruct (Struct) :
+// 			Query(bson Object) : Criteria as per the update should execute
+//			callback (channel) : which returns data to goroutine
+// Output Parameters
+// 		callback(data, error) : returns data, error to channel
+func (c *Collection) RemoveAsync(removeStruct *RemoveStruct, callback chan *Callback) {
+	result, err := c.Remove(removeStruct)
+	cb := new(Callback)
+	cb.Data = result
+	cb.Error = err
+	callback <- cb
+}
+
+// RemoveAll : Function removes all the record from the collection
+// Input Parameters
+//		*RemoveAllStruct (Struct) :
+// Output Parameters
+// 		records(*WriteResult) : returns matched, removed counts
+// 		error : if it was error then return error else nil
+func (c *Collection) RemoveAll(removeAllStruct *RemoveAllStruct) (*WriteResult, error) {
+	return c.RemoveAllCtx(context.Background(), removeAllStruct)
+}
+
+// RemoveAllCtx : Function removes all the record from the collection, aborting when ctx is done
+// Input Parameters
+//		ctx (context.Context) : cancellation and deadline for the operation
+//		*RemoveAllStruct (Struct) :
+// Output Parameters
+// 		records(*WriteResult) : returns matched, removed counts
+// 		error : if it was error then return error else nil
+func (c *Collection) RemoveAllCtx(ctx context.Context, removeAllStruct *RemoveAllStruct) (*WriteResult, error) {
+
+	var records *WriteResult
+	err := c.exec(ctx, func(collection *mongo.Collection) error {
+		result, err := collection.DeleteMany(ctx, emptyDocument)
+		records = removeResult(result)
+		return err
+	}, func(store Store) error {
+		var err error
+		records, err = store.Remove(ctx, c.Name, bson.M{}, true)
+		return err
+	})
+	if err != nil {
+		log.Println(err)
+		return nil, newError("RemoveAll", c.Name, err)
+	}
+
+	return records, nil
+}
+
+// RemoveAllAsync : Function removes all the record from the collection asynchronously
+// Input Parameters
+//		*RemoveAllStruct (Struct) :
+//		callback (channel) : which returns data to goroutine
+// Output Parameters
+// 		callback(data, error) : returns data, error to channel
+func (c *Collection) RemoveAllAsync(removeAllStruct *RemoveAllStruct, callback chan *Callback) {
+	records, err := c.RemoveAll(removeAllStruct)
+	cb := new(Callback)
+	cb.Data = records
+	cb.Error = err
+	callback <- cb
+}
+
+// findByID : Function decodes the record with the given id into result, which must be a pointer
+func (c *Collection) findByID(ctx context.Context, findByIDStruct *FindByIDStruct, result interface{}) error {
+	id, err := normalizeID(findByIDStruct.Id)
+	if err != nil {
+		return err
+	}
+	return c.exec(ctx, func(collection *mongo.Collection) error {
+		filter, err := marshal(bson.M{"_id": id})
+		if err != nil {
+			return err
+		}
+		opts, err := findOptions(ctx, findByIDStruct.Fields, FindOptions{})
+		if err != nil {
+			return err
+		}
+		cursor, err := collection.Find(ctx, filter, opts.SetLimit(-1))
+		if err != nil {
+			return err
+		}
+		defer cursor.Close(ctx)
+		if !cursor.Next(ctx) {
+			if err := cursor.Err(); err != nil {
+				return err
+			}
+			return ErrorNotFound
+		}
+		return decode(cursor.Current, result)
+	}, func(store Store) error {
+		return storeFind(ctx, store, c.Name, bson.M{"_id": id}, findByIDStruct.Fields, FindOptions{}, true, result)
+	})
+}
+
+// find : Function decodes the records matching findStruct into result, which must be a pointer to a slice
+func (c *Collection) find(ctx context.Context, findStruct *FindStruct, result interface{}) error {
+	return c.findRecords(ctx, findStruct.Query, findStruct.Fields, findStruct.options(), result)
+}
+
+// findAll : Function decodes all the records of the collection into result, which must be a pointer to a slice
+func (c *Collection) findAll(ctx context.Context, findAllStruct *FindAllStruct, result interface{}) error {
+	return c.findRecords(ctx, nil, findAllStruct.Fields, findAllStruct.FindOptions, result)
+}
+
+// findRecords : Function decodes the records matching query into result, which must be a pointer to a slice
+func (c *Collection) findRecords(ctx context.Context, query bson.M, fields bson.M, findOpts FindOptions, result interface{}) error {
+	if err := findOpts.validate(); err != nil {
+		return err
+	}
+	return c.exec(ctx, func(collection *mongo.Collection) error {
+		filter, err := marshal(query)
+		if err != nil {
+			return err
+		}
+		opts, err := findOptions(ctx, fields, findOpts)
+		if err != nil {
+			return err
+		}
+		cursor, err := collection.Find(ctx, filter, opts)
+		if err != nil {
+			return err
+		}
+		return decodeAll(ctx, cursor, result)
+	}, func(store Store) error {
+		return storeFind(ctx, store, c.Name, query, fields, findOpts, false, result)
+	})
+}
+
+// findOptions : Function returns the driver options of a find selecting fields, tuned by findOpts
+// The server gives up at the deadline of ctx unless findOpts.MaxTime is set
+func findOptions(ctx context.Context, fields bson.M, findOpts FindOptions) (*options.FindOptions, error) {
+	opts := options.Find()
+	if len(fields) > 0 {
+		projection, err := marshal(fields)
+		if err != nil {
+			return nil, err
+		}
+		opts.SetProjection(projection)
+	}
+	if len(findOpts.Sort) > 0 {
+		keys, err := sortKeys(findOpts.Sort)
+		if err != nil {
+			return nil, err
+		}
+		sort, err := marshal(sortDocument(keys))
+		if err != nil {
+			return nil, err
+		}
+		opts.SetSort(sort)
+	}
+	if findOpts.Skip > 0 {
+		opts.SetSkip(int64(findOpts.Skip))
+	}
+	if findOpts.Limit != 0 {
+		opts.SetLimit(int64(findOpts.Limit))
+	}
+	switch {
+	case findOpts.Hint != nil:
+		index, err := hint(findOpts.Hint)
+		if err != nil {
+			return nil, err
+		}
+		opts.SetHint(index)
+	case findOpts.Snapshot:
+		//the snapshot option is gone from MongoDB 4.0, walking the _id index is what replaces it
+		index, err := hint(bson.D{{Name: "_id", Value: 1}})
+		if err != nil {
+			return nil, err
+		}
+		opts.SetHint(index)
+	}
+	if findOpts.BatchSize > 0 {
+		opts.SetBatchSize(int32(findOpts.BatchSize))
+	}
+	if findOpts.Collation != nil {
+		opts.SetCollation(driverCollation(findOpts.Collation))
+	}
+	if findOpts.MaxTime > 0 {
+		opts.SetMaxTime(findOpts.MaxTime)
+	} else if timeout, ok := maxTime(ctx); ok {
+		opts.SetMaxTime(timeout)
+	}
+	return opts, nil
+}
+
+// writeResult : Function converts the result of an update of the driver, nil when there is none
+func writeResult(result *mongo.UpdateResult) *WriteResult {
+	if result == nil {
+		return nil
+	}
+	return &WriteResult{
+		Matched:    int(result.MatchedCount),
+		Modified:   int(result.ModifiedCount),
+		UpsertedId: fromDriver(result.UpsertedID),
+	}
+}
+
+// removeResult : Function converts the result of a delete of the driver, nil when there is none
+func removeResult(result *mongo.DeleteResult) *WriteResult {
+	if result == nil {
+		return nil
+	}
+	return &WriteResult{Matched: int(result.DeletedCount), Removed: int(result.DeletedCount)}
+}
+
+// insertedIds : Function returns the _id of the documents handed to a Store
+func insertedIds(docs []bson.M) []interface{} {
+	ids := make([]interface{}, len(docs))
+	for i, doc := range docs {
+		ids[i] = doc["_id"]
+	}
+	return ids
+}
@@ -0,0 +1,207 @@
+package gomongo
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	yaml "gopkg.in/yaml.v2"
+)
+
+// redacted replaces secrets when a Config is printed
+const redacted = "xxxxx"
+
+// envSetters maps the environment variables read by ConfigFromEnv (without prefix) to the field they set
+var envSetters = map[string]func(config *Config, value string) error{
+	"URI":                      func(config *Config, value string) error { config.Uri = value; return nil },
+	"DBTYPE":                   func(config *Config, value string) error { config.DbType = value; return nil },
+	"HOSTS":                    func(config *Config, value string) error { config.Hosts = value; return nil },
+	"DATABASE":                 func(config *Config, value string) error { config.Database = value; return nil },
+	"REPLICA_SET":              func(config *Config, value string) error { config.ReplicaSetName = value; return nil },
+	"USERNAME":                 func(config *Config, value string) error { config.Username = value; return nil },
+	"PASSWORD":                 func(config *Config, value string) error { config.Password = value; return nil },
+	"AUTH_DATABASE":            func(config *Config, value string) error { config.AuthDatabase = value; return nil },
+	"DIRECT":                   func(config *Config, value string) (err error) { config.Direct, err = strconv.ParseBool(value); return },
+	"AUTH_MECHANISM":           func(config *Config, value string) error { config.AuthMechanism = value; return nil },
+	"AUTH_SERVICE":             func(config *Config, value string) error { config.AuthService = value; return nil },
+	"AUTH_SERVICE_HOST":        func(config *Config, value string) error { config.AuthServiceHost = value; return nil },
+	"TLS":                      func(config *Config, value string) (err error) { config.tlsOptions().Enabled, err = strconv.ParseBool(value); return },
+	"TLS_CA_FILE":              func(config *Config, value string) error { config.tlsOptions().CAFile = value; return nil },
+	"TLS_CERT_FILE":            func(config *Config, value string) error { config.tlsOptions().CertFile = value; return nil },
+	"TLS_KEY_FILE":             func(config *Config, value string) error { config.tlsOptions().KeyFile = value; return nil },
+	"TLS_SERVER_NAME":          func(config *Config, value string) error { config.tlsOptions().ServerName = value; return nil },
+	"TLS_INSECURE_SKIP_VERIFY": func(config *Config, value string) (err error) { config.tlsOptions().InsecureSkipVerify, err = strconv.ParseBool(value); return },
+	"POOL_LIMIT":               func(config *Config, value string) (err error) { config.PoolLimit, err = strconv.Atoi(value); return },
+	"DIAL_TIMEOUT":             func(config *Config, value string) (err error) { config.DialTimeout, err = time.ParseDuration(value); return },
+	"SOCKET_TIMEOUT":           func(config *Config, value string) (err error) { config.SocketTimeout, err = time.ParseDuration(value); return },
+	"SYNC_TIMEOUT":             func(config *Config, value string) (err error) { config.SyncTimeout, err = time.ParseDuration(value); return },
+	"W": func(config *Config, value string) error {
+		if w, err := strconv.Atoi(value); err == nil {
+			config.writeConcernOptions().W = w
+		} else {
+			config.writeConcernOptions().WMode = value
+		}
+		return nil
+	},
+	"JOURNAL":     func(config *Config, value string) (err error) { config.writeConcernOptions().J, err = strconv.ParseBool(value); return },
+	"WTIMEOUT":    func(config *Config, value string) (err error) { config.writeConcernOptions().WTimeout, err = time.ParseDuration(value); return },
+	"CONSISTENCY": func(config *Config, value string) error { config.Consistency = value; return nil },
+}
+
+// ConfigFromEnv : Function loads a Config from the environment variables named after prefix
+// i.e, with prefix "ORDERS" : ORDERS_URI, ORDERS_HOSTS, ORDERS_DATABASE, ORDERS_USERNAME, ORDERS_PASSWORD,
+// ORDERS_REPLICA_SET, ORDERS_AUTH_DATABASE, ORDERS_AUTH_MECHANISM, ORDERS_TLS, ORDERS_TLS_CA_FILE,
+// ORDERS_POOL_LIMIT, ORDERS_DIAL_TIMEOUT (as "30s"), ORDERS_W, ORDERS_CONSISTENCY, etc.
+// DbType defaults to MONGODB and the loaded Config is validated
+// Input Parameters
+//		prefix(string) : prefix of the variables, joined to their name with "_"; variables aren't prefixed when empty
+// Output Parameters
+//		*Config : the loaded config
+//		error : ErrorInvalidConfig describing every problem found
+func ConfigFromEnv(prefix string) (*Config, error) {
+	if len(prefix) > 0 && !strings.HasSuffix(prefix, "_") {
+		prefix += "_"
+	}
+
+	config := new(Config)
+	var errs []error
+	for name, set := range envSetters {
+		value, ok := os.LookupEnv(prefix + name)
+		if !ok {
+			continue
+		}
+		if err := set(config, value); err != nil {
+			errs = append(errs, fmt.Errorf("%w: bad value for %s%s: %q", ErrorInvalidConfig, prefix, name, value))
+		}
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return loaded(config)
+}
+
+// ConfigFromFile : Function loads a Config from a YAML (.yaml, .yml) or JSON (.json) file
+// Keys are the json / yaml tags of Config, durations are written as "30s"
+// DbType defaults to MONGODB and the loaded Config is validated
+// Input Parameters
+//		path(string) : path of the file
+// Output Parameters
+//		*Config : the loaded config
+//		error : ErrorInvalidConfig describing every problem found
+func ConfigFromFile(path string) (*Config, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml", ".json":
+	default:
+		return nil, fmt.Errorf("%w: unsupported config file %s, expected .yaml, .yml or .json", ErrorInvalidConfig, path)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrorInvalidConfig, err)
+	}
+
+	//JSON is valid YAML, decoding both with YAML lets durations be written as "30s" in both
+	config := new(Config)
+	if err := yaml.UnmarshalStrict(data, config); err != nil {
+		return nil, fmt.Errorf("%w: %s: %v", ErrorInvalidConfig, path, err)
+	}
+	return loaded(config)
+}
+
+// loaded : Function applies the loader defaults to config and validates it
+func loaded(config *Config) (*Config, error) {
+	if len(config.DbType) == 0 {
+		config.DbType = MONGODB
+	}
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// Validate : Function checks config before it is used to connect
+// Output Parameters
+//		error : nil if config is usable, else ErrorInvalidConfig describing every problem found
+func (config *Config) Validate() error {
+	var errs []error
+	invalid := func(format string, args ...interface{}) {
+		errs = append(errs, fmt.Errorf("%w: "+format, append([]interface{}{ErrorInvalidConfig}, args...)...))
+	}
+
+	if len(config.DbType) == 0 {
+		invalid("DbType is missing")
+	} else if err := checkDriver(config.DbType); err != nil {
+		errs = append(errs, err)
+	}
+	if config.DbType != MONGODB {
+		return errors.Join(errs...)
+	}
+
+	hasHosts := len(config.Hosts) > 0 || len(config.Addrs) > 0
+	switch {
+	case len(config.Uri) > 0 && hasHosts:
+		invalid("both Uri and Hosts are set, use one of them")
+	case len(config.Uri) == 0 && !hasHosts:
+		invalid("Uri or Hosts is missing")
+	}
+
+	resolved, err := config.resolve()
+	if err != nil {
+		errs = append(errs, err)
+		return errors.Join(errs...)
+	}
+	if len(resolved.Database) == 0 {
+		invalid("Database is missing")
+	}
+	if _, err := consistencyMode(resolved.Consistency); err != nil {
+		errs = append(errs, err)
+	}
+	if resolved.PoolLimit < 0 {
+		invalid("PoolLimit can't be negative")
+	}
+	if _, err := clientOptions(resolved); err != nil {
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
+
+// String : Function prints config with the password hidden, in the Uri too
+func (config Config) String() string {
+	if len(config.Password) > 0 {
+		config.Password = redacted
+	}
+	if len(config.Uri) > 0 {
+		return fmt.Sprintf("Config{DbType: %s, Uri: %s}", config.DbType, redactURI(config.Uri))
+	}
+	return fmt.Sprintf("Config{DbType: %s, Uri: %s}", config.DbType, config.URI())
+}
+
+// GoString : Function keeps the password hidden when config is printed with %#v
+func (config Config) GoString() string {
+	return config.String()
+}
+
+// redactURI : Function hides the password of a connection string
+func redactURI(uri string) string {
+	rest, ok := strings.CutPrefix(uri, uriScheme)
+	if !ok {
+		return uri
+	}
+	end := strings.IndexAny(rest, "/?")
+	if end < 0 {
+		end = len(rest)
+	}
+	at := strings.LastIndex(rest[:end], "@")
+	if at < 0 {
+		return uri
+	}
+	username, _, hasPassword := strings.Cut(rest[:at], ":")
+	if !hasPassword {
+		return uri
+	}
+	return uriScheme + username + ":" + redacted + rest[at:]
+}
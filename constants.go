@@ -2,6 +2,7 @@ package gomongo
 
 import (
 	"errors"
+	"time"
 )
 
 //Our Database types types
@@ -9,6 +10,48 @@ const (
 	MYSQL   = "mysql"
 	SQLITE  = "sqlite"
 	MONGODB = "mongodb"
+	MEMORY  = "memory"
+)
+
+//Consistency modes of a connection
+const (
+	STRONG    = "strong"    //reads and writes always go to the primary, read preference primary
+	MONOTONIC = "monotonic" //reads go to the primary when available, else a secondary, read preference primaryPreferred
+	EVENTUAL  = "eventual"  //reads go to the nearest member whatever its role, read preference nearest
+
+	//read preferences, as spelled in the readPreference option of a connection string
+	PRIMARY            = "primary"            //same as STRONG
+	PRIMARYPREFERRED   = "primaryPreferred"   //primary when available, else a secondary
+	SECONDARY          = "secondary"          //secondaries only
+	SECONDARYPREFERRED = "secondaryPreferred" //a secondary when available, else the primary
+	NEAREST            = "nearest"            //the nearest member whatever its role
+)
+
+//Authentication mechanisms
+const (
+	SCRAMSHA1   = "SCRAM-SHA-1"   //MongoDB 3.0+, negotiated with SCRAM-SHA-256 when no mechanism is given
+	SCRAMSHA256 = "SCRAM-SHA-256" //MongoDB 4.0+
+	MONGODBCR   = "MONGODB-CR"    //legacy challenge response, MongoDB < 3.0
+	PLAIN       = "PLAIN"         //LDAP proxy authentication
+	X509        = "MONGODB-X509"  //client certificate, requires TLS
+	GSSAPI      = "GSSAPI"        //Kerberos
+)
+
+//Defaults applied by ConnectMongo when the Config leaves them empty
+const (
+	DefaultPoolLimit   = 4000
+	DefaultDialTimeout = 60 * time.Second
+)
+
+//Records per page of FindPage when the PageStruct leaves Limit empty
+const DefaultPageSize = 100
+
+//Defaults applied by Connection.Monitor when the MonitorConfig leaves them empty
+const (
+	DefaultMonitorInterval = 10 * time.Second
+	DefaultPingTimeout     = 5 * time.Second
+	DefaultMinBackoff      = 500 * time.Millisecond
+	DefaultMaxBackoff      = 30 * time.Second
 )
 
 var (
@@ -16,4 +59,17 @@ var (
 	ErrorNotFound      = errors.New("Data not found")
 	ErrorInvalidDBType = errors.New("Invalid database type")
 	ErrorInvalidDriver = errors.New("Unsupported storage driver!")
+	ErrorInvalidConfig = errors.New("Invalid configuration")
+
+	ErrorUnknownConnection = errors.New("Unknown connection")           //name not registered in the Manager
+	ErrorManagerClosed     = errors.New("Connection manager is closed") //Manager used after Close
+
+	//kinds of *Error, match them with errors.Is
+	ErrorDuplicateKey = errors.New("Duplicate key")
+	ErrorTimeout      = errors.New("Operation timed out")
+	ErrorNetwork      = errors.New("Network error")
+	ErrorAuth         = errors.New("Authentication failed")
+	ErrorValidation   = errors.New("Validation failed")
+
+	ErrorInvalidID = errors.New("Invalid id") //also matches ErrorValidation once returned by an operation
 )
@@ -0,0 +1,47 @@
+package gomongo
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// run : Function executes op against the collection bound to ctx
+// The driver aborts a network round trip as soon as ctx is done, so the operation
+// returns with ctx.Err() (wrapped by the driver) when it's cancelled or its deadline passes.
+// Input Parameters
+//		ctx (context.Context) : cancellation and deadline for the operation
+//		op (func) : the operation to execute against the collection
+// Output Parameters
+//		error : ctx.Err() when ctx is already done, otherwise the error returned by op
+func (c *Collection) run(ctx context.Context, op func(collection *mongo.Collection) error) error {
+	return c.conn.run(ctx, func(client *mongo.Client) error {
+		return op(client.Database(c.conn.Database).Collection(c.Name))
+	})
+}
+
+// run : Function executes op against the client of the connection, see Collection.run
+func (conn *Connection) run(ctx context.Context, op func(client *mongo.Client) error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if conn.Client == nil {
+		return mongo.ErrClientDisconnected
+	}
+	return op(conn.Client)
+}
+
+// maxTime : Function returns the time left before the deadline of ctx, so the server stops
+// working on a query nobody is waiting for anymore
+// Output Parameters
+//		time.Duration : the time left
+//		bool : false when ctx has no deadline
+func maxTime(ctx context.Context) (time.Duration, bool) {
+	if deadline, ok := ctx.Deadline(); ok {
+		if timeout := time.Until(deadline); timeout > 0 {
+			return timeout, true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,211 @@
+package gomongo
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Count : Function counts the records matching the query/criteria without reading them
+// Input Parameters
+//		*CountStruct (Struct) :
+// 			Query(bson Object) : Criteria as per the count should execute, every record when empty
+//			Estimated(bool) : count of the collection read from its metadata, fast but may be off after an unclean shutdown
+// Output Parameters
+// 		count(int) : number of matching records
+// 		error : ErrorValidation if Estimated is set with a Query, else error if it failed
+func (c *Collection) Count(countStruct *CountStruct) (int, error) {
+	return c.CountCtx(context.Background(), countStruct)
+}
+
+// CountCtx : Function counts the records matching the query/criteria without reading them, aborting when ctx is done
+// Input Parameters
+//		ctx (context.Context) : cancellation and deadline for the operation
+//		*CountStruct (Struct) :
+// 			Query(bson Object) : Criteria as per the count should execute, every record when empty
+//			Estimated(bool) : count of the collection read from its metadata, fast but may be off after an unclean shutdown
+// Output Parameters
+// 		count(int) : number of matching records
+// 		error : ErrorValidation if Estimated is set with a Query, else error if it failed
+func (c *Collection) CountCtx(ctx context.Context, countStruct *CountStruct) (int, error) {
+	var count int64
+	var err error
+	if countStruct.Estimated && len(countStruct.Query) > 0 {
+		err = fmt.Errorf("%w: an estimated count can't have a Query", ErrorValidation)
+	} else {
+		err = c.exec(ctx, func(collection *mongo.Collection) error {
+			if countStruct.Estimated {
+				count, err = collection.EstimatedDocumentCount(ctx)
+				return err
+			}
+			filter, err := marshal(countStruct.Query)
+			if err != nil {
+				return err
+			}
+			count, err = collection.CountDocuments(ctx, filter)
+			return err
+		}, func(store Store) error {
+			query, err := toDocument(countStruct.Query)
+			if err != nil {
+				return err
+			}
+			//the stores keep no metadata, both counts are exact
+			n, err := store.Count(ctx, c.Name, query)
+			count = int64(n)
+			return err
+		})
+	}
+	if err != nil {
+		log.Println(err)
+		return 0, newError("Count", c.Name, err)
+	}
+	return int(count), nil
+}
+
+// CountAsync : Function counts the records matching the query/criteria without reading them
+// Input Parameters
+//		*CountStruct (Struct) :
+// 			Query(bson Object) : Criteria as per the count should execute, every record when empty
+//			callback (channel) : which returns data to goroutine
+// Output Parameters
+// 		callback(data, error) : returns the count, error to channel
+func (c *Collection) CountAsync(countStruct *CountStruct, callback chan *Callback) {
+	count, err := c.Count(countStruct)
+	cb := new(Callback)
+	cb.Data = count
+	cb.Error = err
+	callback <- cb
+}
+
+// Distinct : Function returns the distinct values of a field among the records matching the query/criteria
+// The elements of an array field are distinct values of their own, the records without the field are skipped
+// Input Parameters
+//		*DistinctStruct (Struct) :
+// 			Field(string) : dotted path of the field
+// 			Query(bson Object) : Criteria as per the distinct should execute, every record when empty
+// Output Parameters
+// 		values([]interface{}) : the distinct values, in no particular order
+// 		error : ErrorValidation if Field is empty, else error if it failed
+func (c *Collection) Distinct(distinctStruct *DistinctStruct) ([]interface{}, error) {
+	return c.DistinctCtx(context.Background(), distinctStruct)
+}
+
+// DistinctCtx : Function returns the distinct values of a field among the matching records, aborting when ctx is done
+// Input Parameters
+//		ctx (context.Context) : cancellation and deadline for the operation
+//		*DistinctStruct (Struct) :
+// 			Field(string) : dotted path of the field
+// 			Query(bson Object) : Criteria as per the distinct should execute, every record when empty
+// Output Parameters
+// 		values([]interface{}) : the distinct values, in no particular order
+// 		error : ErrorValidation if Field is empty, else error if it failed
+func (c *Collection) DistinctCtx(ctx context.Context, distinctStruct *DistinctStruct) ([]interface{}, error) {
+	var values []interface{}
+	var err error
+	field := strings.TrimSpace(distinctStruct.Field)
+	if len(field) == 0 {
+		err = fmt.Errorf("%w: Distinct needs a Field", ErrorValidation)
+	} else {
+		err = c.exec(ctx, func(collection *mongo.Collection) error {
+			filter, err := marshal(distinctStruct.Query)
+			if err != nil {
+				return err
+			}
+			values, err = collection.Distinct(ctx, field, filter)
+			for i := range values {
+				values[i] = fromDriver(values[i])
+			}
+			return err
+		}, func(store Store) error {
+			query, err := toDocument(distinctStruct.Query)
+			if err != nil {
+				return err
+			}
+			values, err = store.Distinct(ctx, c.Name, field, query)
+			return err
+		})
+	}
+	if err != nil {
+		log.Println(err)
+		return nil, newError("Distinct", c.Name, err)
+	}
+	return values, nil
+}
+
+// DistinctAsync : Function returns the distinct values of a field among the records matching the query/criteria
+// Input Parameters
+//		*DistinctStruct (Struct) :
+// 			Field(string) : dotted path of the field
+//			callback (channel) : which returns data to goroutine
+// Output Parameters
+// 		callback(data, error) : returns the values, error to channel
+func (c *Collection) DistinctAsync(distinctStruct *DistinctStruct, callback chan *Callback) {
+	values, err := c.Distinct(distinctStruct)
+	cb := new(Callback)
+	cb.Data = values
+	cb.Error = err
+	callback <- cb
+}
+
+// Exists : Function reports if a record matches the query/criteria, stopping at the first one
+// Input Parameters
+//		*ExistsStruct (Struct) :
+// 			Query(bson Object) : Criteria as per the find should execute
+// Output Parameters
+// 		exists(bool) : true if at least one record matches
+// 		error : if it was error then return error else nil
+func (c *Collection) Exists(existsStruct *ExistsStruct) (bool, error) {
+	return c.ExistsCtx(context.Background(), existsStruct)
+}
+
+// ExistsCtx : Function reports if a record matches the query/criteria, aborting when ctx is done
+// Input Parameters
+//		ctx (context.Context) : cancellation and deadline for the operation
+//		*ExistsStruct (Struct) :
+// 			Query(bson Object) : Criteria as per the find should execute
+// Output Parameters
+// 		exists(bool) : true if at least one record matches
+// 		error : if it was error then return error else nil
+func (c *Collection) ExistsCtx(ctx context.Context, existsStruct *ExistsStruct) (bool, error) {
+	var count int64
+	err := c.exec(ctx, func(collection *mongo.Collection) error {
+		filter, err := marshal(existsStruct.Query)
+		if err != nil {
+			return err
+		}
+		count, err = collection.CountDocuments(ctx, filter, options.Count().SetLimit(1))
+		return err
+	}, func(store Store) error {
+		query, err := toDocument(existsStruct.Query)
+		if err != nil {
+			return err
+		}
+		docs, err := store.Find(ctx, c.Name, query, 0, 1)
+		count = int64(len(docs))
+		return err
+	})
+	if err != nil {
+		log.Println(err)
+		return false, newError("Exists", c.Name, err)
+	}
+	return count > 0, nil
+}
+
+// ExistsAsync : Function reports if a record matches the query/criteria
+// Input Parameters
+//		*ExistsStruct (Struct) :
+// 			Query(bson Object) : Criteria as per the find should execute
+//			callback (channel) : which returns data to goroutine
+// Output Parameters
+// 		callback(data, error) : returns the bool, error to channel
+func (c *Collection) ExistsAsync(existsStruct *ExistsStruct, callback chan *Callback) {
+	exists, err := c.Exists(existsStruct)
+	cb := new(Callback)
+	cb.Data = exists
+	cb.Error = err
+	callback <- cb
+}
@@ -0,0 +1,211 @@
+package gomongo
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"reflect"
+
+	"github.com/globalsign/mgo/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Cursor streams the records of a query one at a time, so a result set larger than the memory can be read
+// MongoDB sends the records in batches of FindOptions.BatchSize, the other drivers read the matching records at once
+// A Cursor isn't safe for concurrent use and must be closed
+//
+//	cursor, err := users.FindCursor(findStr)
+//	defer cursor.Close()
+//	for cursor.Next() {
+//		var user User
+//		err = cursor.Decode(&user)
+//	}
+//	err = cursor.Err()
+type Cursor struct {
+	ctx        context.Context //context the records are read with
+	collection string          //collection name, reported by the errors
+	cursor     *mongo.Cursor   //cursor of the MongoDB driver, nil for the other drivers
+	records    []interface{}   //records of a Store left to read
+	current    interface{}     //record of a Store Next moved to
+	err        error           //error which stopped the iteration
+}
+
+// FindCursor : Function returns a Cursor over the records matching the query/criteria, a FindStruct without Query walks the whole collection
+// Input Parameters
+//		*FindStruct (Struct) :
+// 			Query(bson Object) : Criteria as per the update should execute
+//			FindOptions : sort, skip, limit, batch size, etc
+// Output Parameters
+// 		*Cursor : cursor positioned before the first record
+// 		error : if it was error then return error else nil
+func (c *Collection) FindCursor(findStruct *FindStruct) (*Cursor, error) {
+	return c.FindCursorCtx(context.Background(), findStruct)
+}
+
+// FindCursorCtx : Function returns a Cursor over the records matching the query/criteria, reading them with ctx
+// The cursor stops with ctx.Err() once ctx is done, so ctx must live as long as the cursor is read
+// Input Parameters
+//		ctx (context.Context) : cancellation and deadline for the query and the reads of the cursor
+//		*FindStruct (Struct) :
+// 			Query(bson Object) : Criteria as per the update should execute
+//			FindOptions : sort, skip, limit, batch size, etc
+// Output Parameters
+// 		*Cursor : cursor positioned before the first record
+// 		error : if it was error then return error else nil
+func (c *Collection) FindCursorCtx(ctx context.Context, findStruct *FindStruct) (*Cursor, error) {
+	findOpts := findStruct.options()
+	cursor := &Cursor{ctx: ctx, collection: c.Name}
+	err := findOpts.validate()
+	if err == nil {
+		err = c.exec(ctx, func(collection *mongo.Collection) error {
+			filter, err := marshal(findStruct.Query)
+			if err != nil {
+				return err
+			}
+			opts, err := findOptions(ctx, findStruct.Fields, findOpts)
+			if err != nil {
+				return err
+			}
+			cursor.cursor, err = collection.Find(ctx, filter, opts)
+			return err
+		}, func(store Store) error {
+			return storeFind(ctx, store, c.Name, findStruct.Query, findStruct.Fields, findOpts, false, &cursor.records)
+		})
+	}
+	if err != nil {
+		log.Println(err)
+		return nil, newError("FindCursor", c.Name, err)
+	}
+	return cursor, nil
+}
+
+// FindStream : Function sends the records matching the query/criteria on callback one at a time, decoded like Find does
+// A failure is sent as a last Callback carrying the Error, callback is closed once every record has been sent
+// Stop reading by cancelling ctx, FindStream then returns without sending anything more
+// Input Parameters
+//		ctx (context.Context) : cancellation and deadline for the whole stream
+//		*FindStruct (Struct) :
+// 			Query(bson Object) : Criteria as per the update should execute
+//			FindOptions : sort, skip, limit, batch size, etc
+//		callback (channel) : which returns data to goroutine
+// Output Parameters
+// 		callback(data, error) : sends every record, then the error if there is one, to channel
+func (c *Collection) FindStream(ctx context.Context, findStruct *FindStruct, callback chan *Callback) {
+	stream(ctx, func() (*Cursor, error) {
+		return c.FindCursorCtx(ctx, findStruct)
+	}, callback)
+}
+
+// stream : Function sends every record of the cursor returned by open on callback, then closes it, see FindStream
+func stream(ctx context.Context, open func() (*Cursor, error), callback chan *Callback) {
+	defer close(callback)
+
+	send := func(cb *Callback) bool {
+		select {
+		case callback <- cb:
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}
+
+	cursor, err := open()
+	if err != nil {
+		send(&Callback{Error: err})
+		return
+	}
+	defer cursor.Close()
+
+	for cursor.Next() {
+		var record interface{}
+		if err := cursor.Decode(&record); err != nil {
+			send(&Callback{Error: err})
+			return
+		}
+		if !send(&Callback{Data: record}) {
+			return
+		}
+	}
+	if err := cursor.Err(); err != nil && ctx.Err() == nil {
+		send(&Callback{Error: err})
+	}
+}
+
+// Next : Function moves the cursor to the next record
+// Output Parameters
+//		bool : false once the records are exhausted or the iteration failed, see Err
+func (cursor *Cursor) Next() bool {
+	if cursor.err != nil {
+		return false
+	}
+	if err := cursor.ctx.Err(); err != nil {
+		cursor.fail(err)
+		return false
+	}
+	if cursor.cursor != nil {
+		if cursor.cursor.Next(cursor.ctx) {
+			return true
+		}
+		if err := cursor.cursor.Err(); err != nil {
+			cursor.fail(err)
+		}
+		return false
+	}
+	if len(cursor.records) == 0 {
+		cursor.current = nil
+		return false
+	}
+	cursor.current, cursor.records = cursor.records[0], cursor.records[1:]
+	return true
+}
+
+// Decode : Function decodes the record the cursor is on into result
+// Input Parameters
+//		result (interface{}) : pointer to a struct, a bson.M or an interface{} (which receives a bson.M)
+// Output Parameters
+//		error : ErrorValidation if result isn't a pointer, Next wasn't called or the record doesn't fit result
+func (cursor *Cursor) Decode(result interface{}) error {
+	target := reflect.ValueOf(result)
+	if target.Kind() != reflect.Ptr || target.IsNil() {
+		return newError("Decode", cursor.collection, fmt.Errorf("%w: Decode needs a non nil pointer, not %T", ErrorValidation, result))
+	}
+
+	var err error
+	switch {
+	case cursor.cursor != nil && cursor.cursor.Current != nil:
+		err = decode(cursor.cursor.Current, result)
+	case cursor.current != nil:
+		err = decodeDocument(cursor.current.(bson.M), target.Elem())
+	default:
+		err = fmt.Errorf("%w: Decode called without a record, Next must return true first", ErrorValidation)
+	}
+	if err != nil {
+		return newError("Decode", cursor.collection, err)
+	}
+	return nil
+}
+
+// Err : Function returns the error which stopped the iteration, nil when the records were exhausted
+func (cursor *Cursor) Err() error {
+	return cursor.err
+}
+
+// Close : Function releases the cursor on the server, the cursor can't be read afterwards
+func (cursor *Cursor) Close() error {
+	cursor.records, cursor.current = nil, nil
+	if cursor.cursor == nil {
+		return nil
+	}
+	//the context of the cursor may be done already, the server side cursor must be killed anyway
+	if err := cursor.cursor.Close(context.Background()); err != nil {
+		log.Println(err)
+		return newError("Close", cursor.collection, err)
+	}
+	return nil
+}
+
+// fail : Function stops the iteration with err
+func (cursor *Cursor) fail(err error) {
+	log.Println(err)
+	cursor.err = newError("Next", cursor.collection, err)
+}
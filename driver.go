@@ -0,0 +1,73 @@
+package gomongo
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// DriverFactory returns the DB of a driver, it's called by Init every time the driver is asked for
+type DriverFactory func() DB
+
+var (
+	driversMu sync.RWMutex
+	drivers   = make(map[string]DriverFactory)
+)
+
+func init() {
+	Register(MONGODB, func() DB { return new(MongoDB) })
+}
+
+// Register : Function makes a driver available to Init under name, i.e. from the init function of the package
+// implementing it. The built-in MongoDB driver is registered as MONGODB
+// Like database/sql, Register panics when factory is nil or name is already registered,
+// both being programming errors
+// Input Parameters
+//		name (string) : name of the driver, passed to Init and used as Config.DbType
+//		factory (DriverFactory) : returns the DB of the driver
+func Register(name string, factory DriverFactory) {
+	driversMu.Lock()
+	defer driversMu.Unlock()
+	if factory == nil {
+		panic("gomongo: Register factory of driver " + name + " is nil")
+	}
+	if _, ok := drivers[name]; ok {
+		panic("gomongo: Register called twice for driver " + name)
+	}
+	drivers[name] = factory
+}
+
+// Drivers : Function returns the sorted names of the registered drivers
+func Drivers() []string {
+	driversMu.RLock()
+	defer driversMu.RUnlock()
+	names := make([]string, 0, len(drivers))
+	for name := range drivers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// driver : Function returns the factory registered under name
+func driver(name string) (DriverFactory, bool) {
+	driversMu.RLock()
+	defer driversMu.RUnlock()
+	factory, ok := drivers[name]
+	return factory, ok
+}
+
+// unregister : Function removes a driver, only used by tests
+func unregister(name string) {
+	driversMu.Lock()
+	defer driversMu.Unlock()
+	delete(drivers, name)
+}
+
+// checkDriver : Function reports an unknown DbType
+func checkDriver(name string) error {
+	if _, ok := driver(name); !ok {
+		return fmt.Errorf("%w: unknown DbType %q, registered drivers are %v", ErrorInvalidConfig, name, Drivers())
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package gomongo
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const fakeDriver = "fake"
+
+// fakeDB records the configs it's asked to connect
+type fakeDB struct {
+	configs []*Config
+}
+
+func (f *fakeDB) Connect(config *Config) (*Connection, error) {
+	f.configs = append(f.configs, config)
+	return &Connection{Database: config.Database, Collections: make(map[string]*Collection)}, nil
+}
+
+// registerFakeDriver registers fakeDriver for the duration of the test
+func registerFakeDriver(t *testing.T) *fakeDB {
+	db := new(fakeDB)
+	Register(fakeDriver, func() DB { return db })
+	t.Cleanup(func() { unregister(fakeDriver) })
+	return db
+}
+
+func TestRegister(t *testing.T) {
+	fake := registerFakeDriver(t)
+	assert.Contains(t, Drivers(), MONGODB)
+	assert.Contains(t, Drivers(), fakeDriver)
+
+	db, err := Init(fakeDriver)
+	assert.Nil(t, err)
+	conn, err := db.Connect(&Config{DbType: fakeDriver, Database: TestDatabase})
+	assert.Nil(t, err)
+	assert.Equal(t, TestDatabase, conn.Database)
+	assert.Len(t, fake.configs, 1)
+
+	//the Manager connects through the registry too
+	manager := NewManager()
+	assert.Nil(t, manager.Register("fake", &Config{DbType: fakeDriver, Database: TestDatabase}))
+	_, err = manager.Get("fake")
+	assert.Nil(t, err)
+	assert.Len(t, fake.configs, 2)
+}
+
+func TestRegisterTwice(t *testing.T) {
+	registerFakeDriver(t)
+	assert.Panics(t, func() { Register(fakeDriver, func() DB { return new(fakeDB) }) })
+	assert.Panics(t, func() { Register("nil", nil) })
+}
+
+func TestInitUnknownDriver(t *testing.T) {
+	_, err := Init("postgres")
+	assert.True(t, errors.Is(err, ErrorInvalidDriver))
+
+	db, err := Init(MONGODB)
+	assert.Nil(t, err)
+	assert.IsType(t, new(MongoDB), db)
+}
@@ -0,0 +1,139 @@
+package gomongo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+//server error codes used to classify driver errors
+const (
+	codeUnauthorized         = 13
+	codeAuthenticationFailed = 18
+	codeExceededTimeLimit    = 50
+	codeDocumentValidation   = 121
+)
+
+// Error is returned by every operation when it fails
+// Use errors.Is with one of the Error* kinds (ErrorNotFound, ErrorDuplicateKey, ErrorTimeout,
+// ErrorNetwork, ErrorAuth, ErrorValidation) to classify it, and errors.As to reach the
+// original driver error (e.g. mongo.CommandError, mongo.WriteException)
+type Error struct {
+	Op         string //operation which failed i.e, Find, Update
+	Collection string //collection name, empty for connection level operations
+	Kind       error  //one of the Error* kinds, nil if the error couldn't be classified
+	Err        error  //original error
+}
+
+func (e *Error) Error() string {
+	if e.Collection == "" {
+		return fmt.Sprintf("gomongo: %s: %v", e.Op, e.Err)
+	}
+	return fmt.Sprintf("gomongo: %s %s: %v", e.Op, e.Collection, e.Err)
+}
+
+// Unwrap exposes both the kind and the original error to errors.Is and errors.As
+func (e *Error) Unwrap() []error {
+	if e.Kind == nil {
+		return []error{e.Err}
+	}
+	return []error{e.Kind, e.Err}
+}
+
+// newError : Function wraps err into an *Error classified by its kind
+func newError(op, collection string, err error) error {
+	if err == nil {
+		return nil
+	}
+	var e *Error
+	if errors.As(err, &e) {
+		return err
+	}
+	return &Error{Op: op, Collection: collection, Kind: errorKind(err), Err: err}
+}
+
+// errorKind : Function maps a driver error to one of the Error* kinds
+func errorKind(err error) error {
+	switch {
+	case errors.Is(err, mongo.ErrNoDocuments), errors.Is(err, ErrorNotFound):
+		return ErrorNotFound
+	case errors.Is(err, ErrorDuplicateKey), mongo.IsDuplicateKeyError(err):
+		return ErrorDuplicateKey
+	case errors.Is(err, ErrorValidation), errors.Is(err, ErrorInvalidID):
+		return ErrorValidation
+	case errors.Is(err, context.DeadlineExceeded):
+		return ErrorTimeout
+	case errors.Is(err, context.Canceled):
+		return nil
+	}
+
+	switch errorCode(err) {
+	case codeUnauthorized, codeAuthenticationFailed:
+		return ErrorAuth
+	case codeExceededTimeLimit:
+		return ErrorTimeout
+	case codeDocumentValidation:
+		return ErrorValidation
+	}
+
+	if mongo.IsTimeout(err) {
+		return ErrorTimeout
+	}
+	if mongo.IsNetworkError(err) || errors.Is(err, mongo.ErrClientDisconnected) {
+		return ErrorNetwork
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		if netErr.Timeout() {
+			return ErrorTimeout
+		}
+		return ErrorNetwork
+	}
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return ErrorNetwork
+	}
+
+	//the driver reports these as plain strings
+	msg := err.Error()
+	switch {
+	case strings.Contains(msg, "Authentication failed"), strings.Contains(msg, "auth error"):
+		return ErrorAuth
+	case strings.Contains(msg, "i/o timeout"):
+		return ErrorTimeout
+	case strings.Contains(msg, "server selection error"), strings.Contains(msg, "connection refused"):
+		return ErrorNetwork
+	}
+	return nil
+}
+
+// errorCode : Function returns the server error code carried by err, 0 if there isn't any
+func errorCode(err error) int {
+	var commandErr mongo.CommandError
+	if errors.As(err, &commandErr) {
+		return int(commandErr.Code)
+	}
+	var writeErr mongo.WriteException
+	if errors.As(err, &writeErr) {
+		for _, e := range writeErr.WriteErrors {
+			return e.Code
+		}
+		if writeErr.WriteConcernError != nil {
+			return writeErr.WriteConcernError.Code
+		}
+	}
+	var bulkErr mongo.BulkWriteException
+	if errors.As(err, &bulkErr) {
+		for _, e := range bulkErr.WriteErrors {
+			return e.Code
+		}
+		if bulkErr.WriteConcernError != nil {
+			return bulkErr.WriteConcernError.Code
+		}
+	}
+	return 0
+}
@@ -0,0 +1,65 @@
+package gomongo
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func TestErrorKinds(t *testing.T) {
+	cases := []struct {
+		err  error
+		kind error
+	}{
+		{mongo.ErrNoDocuments, ErrorNotFound},
+		{mongo.WriteException{WriteErrors: []mongo.WriteError{{Code: 11000, Message: "E11000 duplicate key error"}}}, ErrorDuplicateKey},
+		{mongo.BulkWriteException{WriteErrors: []mongo.BulkWriteError{{WriteError: mongo.WriteError{Code: 11000}}}}, ErrorDuplicateKey},
+		{mongo.CommandError{Code: 18, Message: "Authentication failed."}, ErrorAuth},
+		{mongo.CommandError{Code: 13, Message: "not authorized on golang_test"}, ErrorAuth},
+		{mongo.CommandError{Code: 50, Message: "operation exceeded time limit"}, ErrorTimeout},
+		{mongo.WriteException{WriteErrors: []mongo.WriteError{{Code: 121, Message: "Document failed validation"}}}, ErrorValidation},
+		{mongo.ErrClientDisconnected, ErrorNetwork},
+		{context.DeadlineExceeded, ErrorTimeout},
+		{&net.OpError{Op: "read", Err: errors.New("connection reset by peer")}, ErrorNetwork},
+		{io.EOF, ErrorNetwork},
+		{errors.New("server selection error: server selection timeout"), ErrorNetwork},
+		{errors.New("server returned error on SASL authentication step: Authentication failed."), ErrorAuth},
+	}
+
+	for _, c := range cases {
+		err := newError("Find", "users", c.err)
+		assert.True(t, errors.Is(err, c.kind), "%v should be %v", c.err, c.kind)
+		//the driver errors hold slices, errors.Is can't compare them
+		var gomongoErr *Error
+		assert.True(t, errors.As(err, &gomongoErr))
+		assert.Equal(t, c.err, gomongoErr.Err, "%v should wrap the original error", c.err)
+	}
+}
+
+func TestErrorUnclassified(t *testing.T) {
+	original := mongo.CommandError{Code: 2, Message: "bad value"}
+	err := newError("Find", "users", original)
+
+	assert.EqualError(t, err, "gomongo: Find users: "+original.Error())
+	assert.False(t, errors.Is(err, ErrorNotFound))
+
+	var commandErr mongo.CommandError
+	assert.True(t, errors.As(err, &commandErr))
+	assert.Equal(t, int32(2), commandErr.Code)
+
+	var gomongoErr *Error
+	assert.True(t, errors.As(err, &gomongoErr))
+	assert.Nil(t, gomongoErr.Kind)
+	assert.Equal(t, "Find", gomongoErr.Op)
+}
+
+func TestErrorNotWrappedTwice(t *testing.T) {
+	err := newError("FindByID", "users", mongo.ErrNoDocuments)
+	assert.True(t, err == newError("Find", "users", err))
+	assert.Nil(t, newError("Find", "users", nil))
+}
@@ -1,10 +1,14 @@
 package gomongo
 
 import (
+	"context"
+	"fmt"
 	"log"
-	"time"
 
-	mgo "github.com/globalsign/mgo"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+	"go.mongodb.org/mongo-driver/mongo/writeconcern"
 )
 
 // Connect ...
@@ -22,43 +26,145 @@ func (n MongoDB) Connect(config *Config) (*Connection, error) {
 // ConnectMongo ...
 func ConnectMongo(config *Config) (*Connection, error) {
 
-	var mongoDBDialInfo *mgo.DialInfo
-	var mongoSession *mgo.Session
-	var err error
-
-	if len(config.Uri) > 0 {
-		mongoSession, err = mgo.Dial(config.Uri)
-	}else {
-		mongoDBDialInfo = &mgo.DialInfo{
-			Addrs:          []string{config.Hosts},
-			Timeout:        60 * time.Second,
-			Database:       config.Database,
-			ReplicaSetName: config.ReplicaSetName,
-			Mechanism:      "SCRAM-SHA-1",
-			Source:         config.AuthDatabase,
-			Username:       config.Username,
-			Password:       config.Password,
-			Direct:         config.Direct,
-		}
-	
-		mongoSession, err = mgo.DialWithInfo(mongoDBDialInfo)
+	config, err := config.resolve()
+	if err != nil {
+		return nil, err
 	}
-	
+
+	clientOpts, err := clientOptions(config)
 	if err != nil {
-		log.Println("connection error : ", err)
 		return nil, err
 	}
 
-	//mongoSession.SetMode(mgo.Monotonic, true)
-	// mongoSession.SetSafe(&mgo.Safe{})
-	mongoSession.SetSafe(&mgo.Safe{WMode: "majority"})
-	mongoSession.SetPoolLimit(4000)
+	//the client discovers the servers in the background, ping so an unreachable deployment fails here
+	ctx, cancel := context.WithTimeout(context.Background(), *clientOpts.ConnectTimeout)
+	defer cancel()
+	client, err := mongo.Connect(ctx, clientOpts)
+	if err == nil {
+		err = client.Ping(ctx, clientOpts.ReadPreference)
+		if err != nil {
+			client.Disconnect(context.Background())
+		}
+	}
+	if err != nil {
+		log.Println("connection error : ", err)
+		return nil, newError("Connect", "", err)
+	}
 
 	conn := new(Connection)
-	conn.Session = mongoSession
-	conn.Collections = make(map[string]*mgo.Collection)
-	conn.Session.DB(config.Database)
+	conn.Client = client
+	conn.Options = clientOpts
+	conn.Collections = make(map[string]*Collection)
 	conn.Database = config.Database
 
 	return conn, nil
 }
+
+// clientOptions : Function builds the client options of a resolved config, see resolve
+// Credentials and certificates are validated here so a misconfigured connection fails before dialing
+func clientOptions(config *Config) (*options.ClientOptions, error) {
+	mode, err := consistencyMode(config.Consistency)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := options.Client().
+		SetHosts(config.addrs()).
+		SetDirect(config.Direct).
+		SetReadPreference(mode).
+		SetWriteConcern(writeConcern(config.WriteConcern))
+	if len(config.ReplicaSetName) > 0 {
+		opts.SetReplicaSet(config.ReplicaSetName)
+	}
+
+	if len(config.Username) > 0 || len(config.Password) > 0 || len(config.AuthMechanism) > 0 {
+		credential := options.Credential{
+			AuthMechanism: config.AuthMechanism,
+			AuthSource:    config.AuthDatabase,
+			Username:      config.Username,
+			Password:      config.Password,
+			PasswordSet:   len(config.Password) > 0,
+		}
+		if len(config.AuthService) > 0 || len(config.AuthServiceHost) > 0 {
+			credential.AuthMechanismProperties = make(map[string]string)
+			if len(config.AuthService) > 0 {
+				credential.AuthMechanismProperties["SERVICE_NAME"] = config.AuthService
+			}
+			if len(config.AuthServiceHost) > 0 {
+				credential.AuthMechanismProperties["SERVICE_HOST"] = config.AuthServiceHost
+			}
+		}
+		if err := validateCredentials(&credential); err != nil {
+			return nil, err
+		}
+		opts.SetAuth(credential)
+	}
+
+	timeout := config.DialTimeout
+	if timeout == 0 {
+		timeout = DefaultDialTimeout
+	}
+	opts.SetConnectTimeout(timeout)
+	if config.SocketTimeout > 0 {
+		opts.SetSocketTimeout(config.SocketTimeout)
+	}
+	if config.SyncTimeout > 0 {
+		opts.SetServerSelectionTimeout(config.SyncTimeout)
+	}
+
+	poolLimit := config.PoolLimit
+	if poolLimit == 0 {
+		poolLimit = DefaultPoolLimit
+	}
+	opts.SetMaxPoolSize(uint64(poolLimit))
+
+	if config.TLS != nil && config.TLS.Enabled {
+		tlsConfig, err := config.TLS.tlsConfig()
+		if err != nil {
+			return nil, err
+		}
+		opts.SetTLSConfig(tlsConfig)
+	}
+	if config.AuthMechanism == X509 && (config.TLS == nil || !config.TLS.Enabled || len(config.TLS.CertFile) == 0) {
+		return nil, fmt.Errorf("%w: %s requires TLS with a client certificate", ErrorInvalidConfig, X509)
+	}
+	return opts, nil
+}
+
+// consistencyMode : Function maps Config.Consistency to the driver read preference
+// The mgo modes are spelled as read preferences by readPreference first, so URI writes the same mapping
+func consistencyMode(consistency string) (*readpref.ReadPref, error) {
+	switch readPreference(consistency) {
+	case "", PRIMARY:
+		return readpref.Primary(), nil
+	case PRIMARYPREFERRED:
+		return readpref.PrimaryPreferred(), nil
+	case SECONDARY:
+		return readpref.Secondary(), nil
+	case SECONDARYPREFERRED:
+		return readpref.SecondaryPreferred(), nil
+	case NEAREST:
+		return readpref.Nearest(), nil
+	default:
+		return readpref.Primary(), fmt.Errorf("%w: unknown consistency %q", ErrorInvalidConfig, consistency)
+	}
+}
+
+// writeConcern : Function maps Config.WriteConcern to the driver write concern
+func writeConcern(concern *WriteConcern) *writeconcern.WriteConcern {
+	if concern == nil {
+		return writeconcern.Majority()
+	}
+	wc := &writeconcern.WriteConcern{WTimeout: concern.WTimeout}
+	switch {
+	case len(concern.WMode) > 0:
+		wc.W = concern.WMode
+	case concern.W > 0:
+		wc.W = concern.W
+	}
+	//W left at 0 is acknowledged by the primary, not unacknowledged as a driver W of 0 would be
+	if concern.J {
+		wc.Journal = &concern.J
+	}
+	return wc
+}
@@ -0,0 +1,39 @@
+package gomongo
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+	"go.mongodb.org/mongo-driver/mongo/writeconcern"
+)
+
+func TestConsistencyMode(t *testing.T) {
+	for consistency, expected := range map[string]readpref.Mode{"": readpref.PrimaryMode, STRONG: readpref.PrimaryMode, MONOTONIC: readpref.PrimaryPreferredMode, EVENTUAL: readpref.NearestMode, SECONDARY: readpref.SecondaryMode} {
+		mode, err := consistencyMode(consistency)
+		assert.Nil(t, err)
+		assert.Equal(t, expected, mode.Mode())
+	}
+
+	//the connection and the connection string read from the same members
+	for _, consistency := range []string{STRONG, MONOTONIC, EVENTUAL, PRIMARY, PRIMARYPREFERRED, SECONDARY, SECONDARYPREFERRED, NEAREST} {
+		mode, err := consistencyMode(consistency)
+		assert.Nil(t, err)
+		written, err := readpref.ModeFromString(readPreference(consistency))
+		assert.Nil(t, err)
+		assert.Equal(t, mode.Mode(), written, consistency)
+	}
+
+	_, err := consistencyMode("fastest")
+	assert.True(t, errors.Is(err, ErrorInvalidConfig))
+}
+
+func TestWriteConcern(t *testing.T) {
+	journal := true
+	assert.Equal(t, writeconcern.Majority(), writeConcern(nil))
+	assert.Equal(t, &writeconcern.WriteConcern{W: 2, Journal: &journal, WTimeout: 1500 * time.Millisecond}, writeConcern(&WriteConcern{W: 2, J: true, WTimeout: 1500 * time.Millisecond}))
+	assert.Equal(t, &writeconcern.WriteConcern{W: "dc1"}, writeConcern(&WriteConcern{W: 2, WMode: "dc1"}))
+	assert.True(t, writeConcern(&WriteConcern{}).Acknowledged(), "W left at 0 is the server default, not unacknowledged")
+}
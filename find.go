@@ -0,0 +1,99 @@
+package gomongo
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/globalsign/mgo/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/alishavirani/gomongo/internal/document"
+)
+
+// options : Function returns the FindOptions of findStruct, the deprecated Options map filling Skip and Limit when they're 0
+func (findStruct *FindStruct) options() FindOptions {
+	opts := findStruct.FindOptions
+	if limit, ok := findStruct.Options["limit"]; ok && opts.Limit == 0 {
+		opts.Limit = limit
+	}
+	if opts.Skip == 0 {
+		//"skip" is the key shown in the README, "isSkip" the one read by the first versions
+		if skip, ok := findStruct.Options["skip"]; ok {
+			opts.Skip = skip
+		} else if skip, ok := findStruct.Options["isSkip"]; ok {
+			opts.Skip = skip
+		}
+	}
+	return opts
+}
+
+// validate : Function checks the values of opts which the server would reject
+func (opts FindOptions) validate() error {
+	switch {
+	case opts.Skip < 0:
+		return fmt.Errorf("%w: Skip can't be negative", ErrorValidation)
+	case opts.BatchSize < 0:
+		return fmt.Errorf("%w: BatchSize can't be negative", ErrorValidation)
+	case opts.MaxTime < 0:
+		return fmt.Errorf("%w: MaxTime can't be negative", ErrorValidation)
+	}
+	_, err := sortKeys(opts.Sort)
+	return err
+}
+
+// sortKeys : Function parses the sort fields, "-age" sorting descending and "+age" or "age" ascending
+func sortKeys(fields []string) ([]document.SortKey, error) {
+	keys := make([]document.SortKey, 0, len(fields))
+	for _, field := range fields {
+		key := document.SortKey{Path: strings.TrimSpace(field)}
+		switch {
+		case strings.HasPrefix(key.Path, "-"):
+			key.Path, key.Desc = key.Path[1:], true
+		case strings.HasPrefix(key.Path, "+"):
+			key.Path = key.Path[1:]
+		}
+		if len(key.Path) == 0 {
+			return nil, fmt.Errorf("%w: empty sort field in %q", ErrorValidation, fields)
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+// sortDocument : Function returns the sort document of the driver, 1 for ascending and -1 for descending
+func sortDocument(keys []document.SortKey) bson.D {
+	sort := make(bson.D, len(keys))
+	for i, key := range keys {
+		sort[i] = bson.DocElem{Name: key.Path, Value: 1}
+		if key.Desc {
+			sort[i].Value = -1
+		}
+	}
+	return sort
+}
+
+// hint : Function returns the index hint of the driver, an index name is sent as is, index keys are encoded
+func hint(value interface{}) (interface{}, error) {
+	if name, ok := value.(string); ok {
+		return name, nil
+	}
+	return marshal(value)
+}
+
+// driverCollation : Function converts collation for the driver, nil when there is none
+func driverCollation(collation *Collation) *options.Collation {
+	if collation == nil {
+		return nil
+	}
+	return &options.Collation{
+		Locale:          collation.Locale,
+		CaseLevel:       collation.CaseLevel,
+		CaseFirst:       collation.CaseFirst,
+		Strength:        collation.Strength,
+		NumericOrdering: collation.NumericOrdering,
+		Alternate:       collation.Alternate,
+		MaxVariable:     collation.MaxVariable,
+		Normalization:   collation.Normalization,
+		Backwards:       collation.Backwards,
+	}
+}
@@ -0,0 +1,62 @@
+package gomongo
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/globalsign/mgo/bson"
+	"github.com/stretchr/testify/assert"
+	driverbson "go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/alishavirani/gomongo/internal/document"
+)
+
+func TestSortKeys(t *testing.T) {
+	keys, err := sortKeys([]string{"lastName", "-age", "+address.city"})
+	assert.Nil(t, err)
+	assert.Equal(t, []document.SortKey{{Path: "lastName"}, {Path: "age", Desc: true}, {Path: "address.city"}}, keys)
+	assert.Equal(t, bson.D{{Name: "lastName", Value: 1}, {Name: "age", Value: -1}, {Name: "address.city", Value: 1}}, sortDocument(keys))
+
+	_, err = sortKeys([]string{"age", "-"})
+	assert.ErrorIs(t, err, ErrorValidation)
+}
+
+func TestFindOptions(t *testing.T) {
+	opts, err := findOptions(context.Background(), bson.M{"age": 1}, FindOptions{
+		Sort:      []string{"-age", "name"},
+		Skip:      10,
+		Limit:     5,
+		Hint:      "age_1",
+		BatchSize: 100,
+		MaxTime:   time.Second,
+		Collation: &Collation{Locale: "fr", Strength: 2},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, `{"age": {"$numberInt":"-1"},"name": {"$numberInt":"1"}}`, opts.Sort.(driverbson.Raw).String())
+	assert.Equal(t, int64(10), *opts.Skip)
+	assert.Equal(t, int64(5), *opts.Limit)
+	assert.Equal(t, "age_1", opts.Hint)
+	assert.Equal(t, int32(100), *opts.BatchSize)
+	assert.Equal(t, time.Second, *opts.MaxTime)
+	assert.Equal(t, &options.Collation{Locale: "fr", Strength: 2}, opts.Collation)
+
+	//a snapshot walks the _id index, the deadline of the context bounds the server side time
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	opts, err = findOptions(ctx, nil, FindOptions{Snapshot: true})
+	assert.Nil(t, err)
+	assert.Equal(t, `{"_id": {"$numberInt":"1"}}`, opts.Hint.(driverbson.Raw).String())
+	assert.Nil(t, opts.Sort)
+	assert.Nil(t, opts.Limit)
+	assert.InDelta(t, time.Minute, *opts.MaxTime, float64(time.Second))
+
+	findStruct := &FindStruct{Options: map[string]int{"skip": 2, "limit": 3}}
+	assert.Equal(t, FindOptions{Skip: 2, Limit: 3}, findStruct.options(), "the keys of the deprecated Options map still apply")
+	findStruct = &FindStruct{Options: map[string]int{"isSkip": 2}, FindOptions: FindOptions{Skip: 4}}
+	assert.Equal(t, FindOptions{Skip: 4}, findStruct.options())
+
+	assert.ErrorIs(t, FindOptions{Skip: -1}.validate(), ErrorValidation)
+	assert.ErrorIs(t, FindOptions{Sort: []string{""}}.validate(), ErrorValidation)
+}
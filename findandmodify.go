@@ -0,0 +1,307 @@
+package gomongo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/globalsign/mgo/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// modification is what the FindOneAnd operations have in common, op telling them apart
+type modification struct {
+	op        string //FindOneAndUpdate, FindOneAndReplace or FindOneAndDelete, reported by the errors
+	query     bson.M
+	data      interface{} //update or replacement, nil for a delete
+	sort      []string
+	fields    bson.M
+	upsert    bool
+	returnNew bool
+}
+
+// FindOneAndUpdate : Function updates the first record matching the query/criteria and returns it, in a single atomic step
+// Two callers racing for the same record never both get it, i.e claiming a job:
+//
+//	job, err := jobs.FindOneAndUpdate(&FindOneAndUpdateStruct{
+//		Query: bson.M{"status": "pending"}, Data: bson.M{"$set": bson.M{"status": "running"}}, Sort: []string{"createdAt"}, ReturnNew: true})
+//
+// Input Parameters
+//		*FindOneAndUpdateStruct (Struct) :
+// 			Query(bson Object) : Criteria as per the update should execute
+//			Data(interface{}) : update operators
+//			Sort([]string) : picks the record when several match
+//			Fields(bson Object) : fields of the returned record
+//			Upsert(bool) : insert a record when none matches
+//			ReturnNew(bool) : return the record as updated instead of as it was
+// Output Parameters
+// 		record(interface{}) : the record before or after the update, nil when Upsert inserted one and ReturnNew is false
+// 		error : ErrorNotFound if no record matched, ErrorValidation if Data has no update operators, else error if it failed
+func (c *Collection) FindOneAndUpdate(findOneAndUpdateStruct *FindOneAndUpdateStruct) (interface{}, error) {
+	return c.FindOneAndUpdateCtx(context.Background(), findOneAndUpdateStruct)
+}
+
+// FindOneAndUpdateCtx : Function updates the first record matching the query/criteria and returns it, aborting when ctx is done
+// Input Parameters
+//		ctx (context.Context) : cancellation and deadline for the operation
+//		*FindOneAndUpdateStruct (Struct) :
+// 			Query(bson Object) : Criteria as per the update should execute
+//			Data(interface{}) : update operators
+//			Sort([]string) : picks the record when several match
+//			Fields(bson Object) : fields of the returned record
+//			Upsert(bool) : insert a record when none matches
+//			ReturnNew(bool) : return the record as updated instead of as it was
+// Output Parameters
+// 		record(interface{}) : the record before or after the update, nil when Upsert inserted one and ReturnNew is false
+// 		error : ErrorNotFound if no record matched, ErrorValidation if Data has no update operators, else error if it failed
+func (c *Collection) FindOneAndUpdateCtx(ctx context.Context, findOneAndUpdateStruct *FindOneAndUpdateStruct) (interface{}, error) {
+	return c.findAndModify(ctx, modification{
+		op:        "FindOneAndUpdate",
+		query:     findOneAndUpdateStruct.Query,
+		data:      findOneAndUpdateStruct.Data,
+		sort:      findOneAndUpdateStruct.Sort,
+		fields:    findOneAndUpdateStruct.Fields,
+		upsert:    findOneAndUpdateStruct.Upsert,
+		returnNew: findOneAndUpdateStruct.ReturnNew,
+	})
+}
+
+// FindOneAndUpdateAsync : Function updates the first record matching the query/criteria and returns it
+// Input Parameters
+//		*FindOneAndUpdateStruct (Struct) :
+// 			Query(bson Object) : Criteria as per the update should execute
+//			Data(interface{}) : update operators
+//			callback (channel) : which returns data to goroutine
+// Output Parameters
+// 		callback(data, error) : returns the record, error to channel
+func (c *Collection) FindOneAndUpdateAsync(findOneAndUpdateStruct *FindOneAndUpdateStruct, callback chan *Callback) {
+	record, err := c.FindOneAndUpdate(findOneAndUpdateStruct)
+	cb := new(Callback)
+	cb.Data = record
+	cb.Error = err
+	callback <- cb
+}
+
+// FindOneAndReplace : Function replaces the first record matching the query/criteria and returns it, in a single atomic step
+// Input Parameters
+//		*FindOneAndReplaceStruct (Struct) :
+// 			Query(bson Object) : Criteria as per the replace should execute
+//			Data(interface{}) : the new record, without update operators
+//			Sort([]string) : picks the record when several match
+//			Fields(bson Object) : fields of the returned record
+//			Upsert(bool) : insert Data when no record matches
+//			ReturnNew(bool) : return the new record instead of the replaced one
+// Output Parameters
+// 		record(interface{}) : the record before or after the replace, nil when Upsert inserted one and ReturnNew is false
+// 		error : ErrorNotFound if no record matched, ErrorValidation if Data has update operators, else error if it failed
+func (c *Collection) FindOneAndReplace(findOneAndReplaceStruct *FindOneAndReplaceStruct) (interface{}, error) {
+	return c.FindOneAndReplaceCtx(context.Background(), findOneAndReplaceStruct)
+}
+
+// FindOneAndReplaceCtx : Function replaces the first record matching the query/criteria and returns it, aborting when ctx is done
+// Input Parameters
+//		ctx (context.Context) : cancellation and deadline for the operation
+//		*FindOneAndReplaceStruct (Struct) :
+// 			Query(bson Object) : Criteria as per the replace should execute
+//			Data(interface{}) : the new record, without update operators
+//			Sort([]string) : picks the record when several match
+//			Fields(bson Object) : fields of the returned record
+//			Upsert(bool) : insert Data when no record matches
+//			ReturnNew(bool) : return the new record instead of the replaced one
+// Output Parameters
+// 		record(interface{}) : the record before or after the replace, nil when Upsert inserted one and ReturnNew is false
+// 		error : ErrorNotFound if no record matched, ErrorValidation if Data has update operators, else error if it failed
+func (c *Collection) FindOneAndReplaceCtx(ctx context.Context, findOneAndReplaceStruct *FindOneAndReplaceStruct) (interface{}, error) {
+	return c.findAndModify(ctx, modification{
+		op:        "FindOneAndReplace",
+		query:     findOneAndReplaceStruct.Query,
+		data:      findOneAndReplaceStruct.Data,
+		sort:      findOneAndReplaceStruct.Sort,
+		fields:    findOneAndReplaceStruct.Fields,
+		upsert:    findOneAndReplaceStruct.Upsert,
+		returnNew: findOneAndReplaceStruct.ReturnNew,
+	})
+}
+
+// FindOneAndReplaceAsync : Function replaces the first record matching the query/criteria and returns it
+// Input Parameters
+//		*FindOneAndReplaceStruct (Struct) :
+// 			Query(bson Object) : Criteria as per the replace should execute
+//			Data(interface{}) : the new record, without update operators
+//			callback (channel) : which returns data to goroutine
+// Output Parameters
+// 		callback(data, error) : returns the record, error to channel
+func (c *Collection) FindOneAndReplaceAsync(findOneAndReplaceStruct *FindOneAndReplaceStruct, callback chan *Callback) {
+	record, err := c.FindOneAndReplace(findOneAndReplaceStruct)
+	cb := new(Callback)
+	cb.Data = record
+	cb.Error = err
+	callback <- cb
+}
+
+// FindOneAndDelete : Function removes the first record matching the query/criteria and returns it, in a single atomic step
+// Input Parameters
+//		*FindOneAndDeleteStruct (Struct) :
+// 			Query(bson Object) : Criteria as per the remove should execute
+//			Sort([]string) : picks the record when several match
+//			Fields(bson Object) : fields of the returned record
+// Output Parameters
+// 		record(interface{}) : the removed record
+// 		error : ErrorNotFound if no record matched, else error if it failed
+func (c *Collection) FindOneAndDelete(findOneAndDeleteStruct *FindOneAndDeleteStruct) (interface{}, error) {
+	return c.FindOneAndDeleteCtx(context.Background(), findOneAndDeleteStruct)
+}
+
+// FindOneAndDeleteCtx : Function removes the first record matching the query/criteria and returns it, aborting when ctx is done
+// Input Parameters
+//		ctx (context.Context) : cancellation and deadline for the operation
+//		*FindOneAndDeleteStruct (Struct) :
+// 			Query(bson Object) : Criteria as per the remove should execute
+//			Sort([]string) : picks the record when several match
+//			Fields(bson Object) : fields of the returned record
+// Output Parameters
+// 		record(interface{}) : the removed record
+// 		error : ErrorNotFound if no record matched, else error if it failed
+func (c *Collection) FindOneAndDeleteCtx(ctx context.Context, findOneAndDeleteStruct *FindOneAndDeleteStruct) (interface{}, error) {
+	return c.findAndModify(ctx, modification{
+		op:     "FindOneAndDelete",
+		query:  findOneAndDeleteStruct.Query,
+		sort:   findOneAndDeleteStruct.Sort,
+		fields: findOneAndDeleteStruct.Fields,
+	})
+}
+
+// FindOneAndDeleteAsync : Function removes the first record matching the query/criteria and returns it
+// Input Parameters
+//		*FindOneAndDeleteStruct (Struct) :
+// 			Query(bson Object) : Criteria as per the remove should execute
+//			callback (channel) : which returns data to goroutine
+// Output Parameters
+// 		callback(data, error) : returns the removed record, error to channel
+func (c *Collection) FindOneAndDeleteAsync(findOneAndDeleteStruct *FindOneAndDeleteStruct, callback chan *Callback) {
+	record, err := c.FindOneAndDelete(findOneAndDeleteStruct)
+	cb := new(Callback)
+	cb.Data = record
+	cb.Error = err
+	callback <- cb
+}
+
+// findAndModify : Function runs a FindOneAnd operation on MongoDB or on the Store of the connection
+func (c *Collection) findAndModify(ctx context.Context, m modification) (interface{}, error) {
+	var record interface{}
+	err := m.validate()
+	if err == nil {
+		err = c.exec(ctx, func(collection *mongo.Collection) error {
+			var err error
+			record, err = m.run(ctx, collection)
+			return err
+		}, func(store Store) error {
+			var err error
+			record, err = storeFindAndModify(ctx, store, c.Name, m)
+			return err
+		})
+	}
+	if err != nil {
+		log.Println(err)
+		return nil, newError(m.op, c.Name, err)
+	}
+	return record, nil
+}
+
+// validate : Function checks the sort of m, and that its data is an update or a replacement as op expects
+func (m modification) validate() error {
+	if _, err := sortKeys(m.sort); err != nil {
+		return err
+	}
+	if m.op == "FindOneAndDelete" {
+		return nil
+	}
+	data, err := marshal(m.data)
+	if err != nil {
+		return err
+	}
+	switch replacement := isReplacement(data); {
+	case m.op == "FindOneAndUpdate" && replacement:
+		return fmt.Errorf("%w: FindOneAndUpdate needs update operators, use FindOneAndReplace to replace the record", ErrorValidation)
+	case m.op == "FindOneAndReplace" && !replacement:
+		return fmt.Errorf("%w: FindOneAndReplace can't have update operators, use FindOneAndUpdate", ErrorValidation)
+	}
+	return nil
+}
+
+// run : Function runs m with the findAndModify command of MongoDB
+func (m modification) run(ctx context.Context, collection *mongo.Collection) (interface{}, error) {
+	filter, err := marshal(m.query)
+	if err != nil {
+		return nil, err
+	}
+	var sort, projection interface{}
+	if len(m.sort) > 0 {
+		keys, err := sortKeys(m.sort)
+		if err != nil {
+			return nil, err
+		}
+		if sort, err = marshal(sortDocument(keys)); err != nil {
+			return nil, err
+		}
+	}
+	if len(m.fields) > 0 {
+		if projection, err = marshal(m.fields); err != nil {
+			return nil, err
+		}
+	}
+	returnDocument := options.Before
+	if m.returnNew {
+		returnDocument = options.After
+	}
+	timeout, hasTimeout := maxTime(ctx)
+
+	var result *mongo.SingleResult
+	switch m.op {
+	case "FindOneAndDelete":
+		opts := options.FindOneAndDelete().SetSort(sort).SetProjection(projection)
+		if hasTimeout {
+			opts.SetMaxTime(timeout)
+		}
+		result = collection.FindOneAndDelete(ctx, filter, opts)
+	case "FindOneAndReplace":
+		data, err := marshal(m.data)
+		if err != nil {
+			return nil, err
+		}
+		opts := options.FindOneAndReplace().SetSort(sort).SetProjection(projection).SetUpsert(m.upsert).SetReturnDocument(returnDocument)
+		if hasTimeout {
+			opts.SetMaxTime(timeout)
+		}
+		result = collection.FindOneAndReplace(ctx, filter, data, opts)
+	default:
+		data, err := marshal(m.data)
+		if err != nil {
+			return nil, err
+		}
+		opts := options.FindOneAndUpdate().SetSort(sort).SetProjection(projection).SetUpsert(m.upsert).SetReturnDocument(returnDocument)
+		if hasTimeout {
+			opts.SetMaxTime(timeout)
+		}
+		result = collection.FindOneAndUpdate(ctx, filter, data, opts)
+	}
+
+	raw, err := result.Raw()
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		if m.upsert && !m.returnNew {
+			//nothing matched so a record was inserted, it had no previous version to return
+			return nil, nil
+		}
+		return nil, ErrorNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	var record interface{}
+	if err := decode(raw, &record); err != nil {
+		return nil, err
+	}
+	return record, nil
+}
@@ -1,10 +1,7 @@
 package gomongo
 
 import (
-	"log"
-
-	mgo "github.com/globalsign/mgo"
-	"github.com/globalsign/mgo/bson"
+	"context"
 )
 
 // BulkInsert : Function inserts the data in bulk to the collection
@@ -12,22 +9,22 @@ import (
 // 		*BulkInsertStruct (Struct) :
 //	 		Data([] interfaces{}]) : the object which has to be inserted
 // Output Parameters
-// 		record(*mgo.BulkResult) : return count of matched and modified results
+// 		result(*WriteResult) : returns the _id of the inserted records
 // 		error : if it was error then return error else nil
-func (conn *Connection) BulkInsert(bulkInsertStruct *BulkInsertStruct) (*mgo.BulkResult, error) {
-	var info *mgo.BulkResult
-	sessionCopy := conn.Session.Copy()
-	defer sessionCopy.Close()
-	collection := sessionCopy.DB(conn.Database).C(conn.Collection)
-	bulk := collection.Bulk()
-	bulk.Unordered()
-	bulk.Insert(bulkInsertStruct.Data...)
-	info, err := bulk.Run()
-	if err != nil {
-		log.Println(err)
-		info = nil
-	}
-	return info, err
+func (conn *Connection) BulkInsert(bulkInsertStruct *BulkInsertStruct) (*WriteResult, error) {
+	return conn.C(conn.Collection).BulkInsert(bulkInsertStruct)
+}
+
+// BulkInsertCtx : Function inserts the data in bulk to the collection, aborting when ctx is done
+// Input Parameters :
+// 		ctx (context.Context) : cancellation and deadline for the operation
+// 		*BulkInsertStruct (Struct) :
+//	 		Data([] interfaces{}]) : the object which has to be inserted
+// Output Parameters
+// 		result(*WriteResult) : returns the _id of the inserted records
+// 		error : if it was error then return error else nil
+func (conn *Connection) BulkInsertCtx(ctx context.Context, bulkInsertStruct *BulkInsertStruct) (*WriteResult, error) {
+	return conn.C(conn.Collection).BulkInsertCtx(ctx, bulkInsertStruct)
 }
 
 // Insert : Function inserts the data object into the collection
@@ -35,18 +32,22 @@ func (conn *Connection) BulkInsert(bulkInsertStruct *BulkInsertStruct) (*mgo.Bul
 // 		*InsertStruct (Struct) :
 //	 		Data([] interfaces{}]) : the object which has to be inserted
 // Output Parameters
+// 		result(*WriteResult) : returns the _id of the record, the ObjectId given to it when it had none
 // 		error : if it was error then return error else nil
-func (conn *Connection) Insert(insertStruct *InsertStruct) error {
-	var err error
-
-	sessionCopy := conn.Session.Copy()
-	defer sessionCopy.Close()
-	collection := sessionCopy.DB(conn.Database).C(conn.Collection)
-	err = collection.Insert(&insertStruct.Data)
-	if err != nil {
-		log.Println(err)
-	}
-	return err
+func (conn *Connection) Insert(insertStruct *InsertStruct) (*WriteResult, error) {
+	return conn.C(conn.Collection).Insert(insertStruct)
+}
+
+// InsertCtx : Function inserts the data object into the collection, aborting when ctx is done
+// Input Parameters :
+// 		ctx (context.Context) : cancellation and deadline for the operation
+// 		*InsertStruct (Struct) :
+//	 		Data([] interfaces{}]) : the object which has to be inserted
+// Output Parameters
+// 		result(*WriteResult) : returns the _id of the record, the ObjectId given to it when it had none
+// 		error : if it was error then return error else nil
+func (conn *Connection) InsertCtx(ctx context.Context, insertStruct *InsertStruct) (*WriteResult, error) {
+	return conn.C(conn.Collection).InsertCtx(ctx, insertStruct)
 }
 
 // InsertAsync : Function inserts the data object into the collection
@@ -55,94 +56,85 @@ func (conn *Connection) Insert(insertStruct *InsertStruct) error {
 //	 		Data([] interfaces{}]) : the object which has to be inserted
 //			callback (channel) : which returns data to goroutine
 // Output Parameters
-// 		callback(data, error) : sends data, error back to channel
+// 		callback(data, error) : sends the *WriteResult, error back to channel
 func (conn *Connection) InsertAsync(insertStruct *InsertStruct, callback chan *Callback) {
-	var err error
-	err = conn.Insert(insertStruct)
-	cb := new(Callback)
-	cb.Data = nil
-	cb.Error = err
-	callback <- cb
+	conn.C(conn.Collection).InsertAsync(insertStruct, callback)
 }
 
 // Update : Function Updates the record into the collection
 // Input Parameters :
 // 		*UpdateStruct (Struct) :
 //	 		Data([] interfaces{}]) : the object which has to be inserted
-// 			Id(string) : The record id whose details have to be updated
+// 			Id(interface{}) : The record id (Hexadecimal ObjectId, StringID, int, UUID, etc) whose details have to be updated
 // Output Parameters
-// 		error : if it was error then return error else nil
+// 		result(*WriteResult) : returns matched, modified counts
+// 		error : ErrorNotFound if no record matched, else error if it failed, nil on success
 
-func (conn *Connection) Update(updateStruct *UpdateStruct) error {
-	sessionCopy := conn.Session.Copy()
-	defer sessionCopy.Close()
-	collection := sessionCopy.DB(conn.Database).C(conn.Collection)
-	err := collection.UpdateId(bson.ObjectIdHex(updateStruct.Id), updateStruct.Data)
-	if err != nil {
-		log.Println(err)
-		if err.Error() == MongoErrorNotFound.Error() {
-			err = nil
-		}
-		return err
-	}
-	return err
+func (conn *Connection) Update(updateStruct *UpdateStruct) (*WriteResult, error) {
+	return conn.C(conn.Collection).Update(updateStruct)
+}
+
+// UpdateCtx : Function Updates the record into the collection, aborting when ctx is done
+// Input Parameters :
+// 		ctx (context.Context) : cancellation and deadline for the operation
+// 		*UpdateStruct (Struct) :
+//	 		Data([] interfaces{}]) : the object which has to be inserted
+// 			Id(interface{}) : The record id (Hexadecimal ObjectId, StringID, int, UUID, etc) whose details have to be updated
+// Output Parameters
+// 		result(*WriteResult) : returns matched, modified counts
+// 		error : ErrorNotFound if no record matched, else error if it failed, nil on success
+func (conn *Connection) UpdateCtx(ctx context.Context, updateStruct *UpdateStruct) (*WriteResult, error) {
+	return conn.C(conn.Collection).UpdateCtx(ctx, updateStruct)
 }
 
 // UpdateAsync : Function Updates the record into the collection
 // Input Parameters
 // 		*UpdateStruct (Struct) :
 //	 		Data([] interfaces{}]) : the object which has to be inserted
-// 			Id(string) : The record id whose details have to be updated
+// 			Id(interface{}) : The record id (Hexadecimal ObjectId, StringID, int, UUID, etc) whose details have to be updated
 //			callback (channel) : which returns data to goroutine
 // Output Parameters
-// 		callback(data, error) : sends if error back to channel
+// 		callback(data, error) : sends the *WriteResult, error back to channel
 func (conn *Connection) UpdateAsync(updateStruct *UpdateStruct, callback chan *Callback) {
-	var err error
-	err = conn.Update(updateStruct)
-	cb := new(Callback)
-	cb.Data = nil
-	cb.Error = err
-	callback <- cb
+	conn.C(conn.Collection).UpdateAsync(updateStruct, callback)
 }
 
 // Upsert : Function Updates the record if found else inserts as a new record into the collection
 // Input Parameters :
 // 		*UpsertStruct (Struct) :
 //	 		Data([] interfaces{}]) : the object which has to be inserted
-// 			Id(string) : The record id whose details have to be updated
+// 			Id(interface{}) : The record id (Hexadecimal ObjectId, StringID, int, UUID, etc) whose details have to be updated
 // Output Parameters
-// 		info(*mgo.ChangeInfo) : returns updated, removed, matched, record details count
+// 		info(*WriteResult) : returns matched, modified counts and the _id of the inserted record
 // 		error : if it was error then return error else nil
 
-func (conn *Connection) Upsert(upsertStruct *UpsertStruct) (*mgo.ChangeInfo, error) {
-	sessionCopy := conn.Session.Copy()
-	defer sessionCopy.Close()
-	collection := sessionCopy.DB(conn.Database).C(conn.Collection)
-	info, err := collection.UpsertId(bson.ObjectIdHex(upsertStruct.Id), upsertStruct.Data)
-	if err != nil {
-		log.Println(err)
-		if err.Error() == MongoErrorNotFound.Error() {
-			err = nil
-		}
-	}
-	return info, err
+func (conn *Connection) Upsert(upsertStruct *UpsertStruct) (*WriteResult, error) {
+	return conn.C(conn.Collection).Upsert(upsertStruct)
+}
+
+// UpsertCtx : Function Updates the record if found else inserts as a new record, aborting when ctx is done
+// Input Parameters :
+// 		ctx (context.Context) : cancellation and deadline for the operation
+// 		*UpsertStruct (Struct) :
+//	 		Data([] interfaces{}]) : the object which has to be inserted
+// 			Id(interface{}) : The record id (Hexadecimal ObjectId, StringID, int, UUID, etc) whose details have to be updated
+// Output Parameters
+// 		info(*WriteResult) : returns matched, modified counts and the _id of the inserted record
+// 		error : if it was error then return error else nil
+func (conn *Connection) UpsertCtx(ctx context.Context, upsertStruct *UpsertStruct) (*WriteResult, error) {
+	return conn.C(conn.Collection).UpsertCtx(ctx, upsertStruct)
 }
 
 // UpsertAsync : Function Updates the record if found else inserts as a new record into the collection
 // Input Parameters :
 // 		*UpsertStruct (Struct) :
 //	 		Data([] interfaces{}]) : the object which has to be inserted
-// 			Id(string) : The record id whose details have to be updated
+// 			Id(interface{}) : The record id (Hexadecimal ObjectId, StringID, int, UUID, etc) whose details have to be updated
 // Output Parameters
 // 		callback(data, error) : sends updated, matched, modified counts back to channel
 
 func (conn *Connection) UpsertAsync(upsertStruct *UpsertStruct, callback chan *Callback) {
-
-	info, err := conn.Upsert(upsertStruct)
-	cb := new(Callback)
-	cb.Data = info
-	cb.Error = err
-	callback <- cb
+	conn.C(conn.Collection).UpsertAsync(upsertStruct, callback)
 }
 
 // UpdateOne : Function Updates the matching record into the collection
@@ -151,21 +143,24 @@ func (conn *Connection) UpsertAsync(upsertStruct *UpsertStruct, callback chan *C
 //	 		Data(interfaces{}]) : the object which has to be inserted
 // 			Query(bson Object) : Criteria as per the update should execute
 // Output Parameters
-// 		error : if it was error then return error else nil
+// 		result(*WriteResult) : returns matched, modified counts
+// 		error : ErrorNotFound if no record matched, else error if it failed, nil on success
 
-func (conn *Connection) UpdateOne(updateOneStruct UpdateOneStruct) error {
-	sessionCopy := conn.Session.Copy()
-	defer sessionCopy.Close()
-	collection := sessionCopy.DB(conn.Database).C(conn.Collection)
-	err := collection.Update(updateOneStruct.Query, updateOneStruct.Data)
-	if err != nil {
-		log.Println(err)
-		if err.Error() == MongoErrorNotFound.Error() {
-			err = nil
-		}
-		return err
-	}
-	return nil
+func (conn *Connection) UpdateOne(updateOneStruct UpdateOneStruct) (*WriteResult, error) {
+	return conn.C(conn.Collection).UpdateOne(updateOneStruct)
+}
+
+// UpdateOneCtx : Function Updates the matching record into the collection, aborting when ctx is done
+// Input Parameters
+// 		ctx (context.Context) : cancellation and deadline for the operation
+// 		updateOneStruct (Struct) :
+//	 		Data(interfaces{}]) : the object which has to be inserted
+// 			Query(bson Object) : Criteria as per the update should execute
+// Output Parameters
+// 		result(*WriteResult) : returns matched, modified counts
+// 		error : ErrorNotFound if no record matched, else error if it failed, nil on success
+func (conn *Connection) UpdateOneCtx(ctx context.Context, updateOneStruct UpdateOneStruct) (*WriteResult, error) {
+	return conn.C(conn.Collection).UpdateOneCtx(ctx, updateOneStruct)
 }
 
 // UpdateAll : Function Updates all the record into the collection
@@ -174,22 +169,24 @@ func (conn *Connection) UpdateOne(updateOneStruct UpdateOneStruct) error {
 //	 		Data([] interfaces{}]) : the object which has to be inserted
 // 			Query(bson Object) : Criteria as per the update should execute
 // Output Parameters
-// 		records(*mgo.ChangeInfo) : returns updated, matched, modified counts
+// 		records(*WriteResult) : returns matched, modified counts
 // 		error : if it was error then return error else nil
 
-func (conn *Connection) UpdateAll(updateAllStruct UpdateAllStruct) (*mgo.ChangeInfo, error) {
-	sessionCopy := conn.Session.Copy()
-	defer sessionCopy.Close()
-	collection := sessionCopy.DB(conn.Database).C(conn.Collection)
-	records, err := collection.UpdateAll(updateAllStruct.Query, updateAllStruct.Data)
-	if err != nil {
-		log.Println(err)
-		if err.Error() == MongoErrorNotFound.Error() {
-			err = nil
-		}
-		return nil, err
-	}
-	return records, nil
+func (conn *Connection) UpdateAll(updateAllStruct UpdateAllStruct) (*WriteResult, error) {
+	return conn.C(conn.Collection).UpdateAll(updateAllStruct)
+}
+
+// UpdateAllCtx : Function Updates all the record into the collection, aborting when ctx is done
+// Input Parameters
+// 		ctx (context.Context) : cancellation and deadline for the operation
+// 		UpdateAllStruct (Struct) :
+//	 		Data([] interfaces{}]) : the object which has to be inserted
+// 			Query(bson Object) : Criteria as per the update should execute
+// Output Parameters
+// 		records(*WriteResult) : returns matched, modified counts
+// 		error : if it was error then return error else nil
+func (conn *Connection) UpdateAllCtx(ctx context.Context, updateAllStruct UpdateAllStruct) (*WriteResult, error) {
+	return conn.C(conn.Collection).UpdateAllCtx(ctx, updateAllStruct)
 }
 
 // UpdateAllAsync : Function Updates all the record into the collection
@@ -202,11 +199,7 @@ func (conn *Connection) UpdateAll(updateAllStruct UpdateAllStruct) (*mgo.ChangeI
 // 		callback(data, error) : sends updated, matched, modified counts back to channel
 
 func (conn *Connection) UpdateAllAsync(updateAllStruct UpdateAllStruct, callback chan *Callback) {
-	records, err := conn.UpdateAll(updateAllStruct)
-	cb := new(Callback)
-	cb.Data = records
-	cb.Error = err
-	callback <- cb
+	conn.C(conn.Collection).UpdateAllAsync(updateAllStruct, callback)
 }
 
 // UpsertAll : Function Upserts record into the collection if not found else updates
@@ -215,22 +208,24 @@ func (conn *Connection) UpdateAllAsync(updateAllStruct UpdateAllStruct, callback
 //		Data([] interfaces{}]) : the object which has to be inserted
 //		Query(bson Object) : Criteria as per the update should execute
 // Output Parameters
-//	records(*mgo.ChangeInfo) : returns updated, matched, modified counts
+//	records(*WriteResult) : returns matched, modified counts
 //	error : if it was error then return error else nil
 
-func (conn *Connection) UpsertAll(upsertAllStruct *UpsertAllStruct) (*mgo.ChangeInfo, error) {
-	sessionCopy := conn.Session.Copy()
-	defer sessionCopy.Close()
-	collection := sessionCopy.DB(conn.Database).C(conn.Collection)
-	records, err := collection.Upsert(upsertAllStruct.Query, upsertAllStruct.Data)
-	if err != nil {
-		log.Println(err)
-		if err.Error() == MongoErrorNotFound.Error() {
-			err = nil
-		}
-		return nil, err
-	}
-	return records, nil
+func (conn *Connection) UpsertAll(upsertAllStruct *UpsertAllStruct) (*WriteResult, error) {
+	return conn.C(conn.Collection).UpsertAll(upsertAllStruct)
+}
+
+// UpsertAllCtx : Function Upserts record into the collection if not found else updates, aborting when ctx is done
+// Input Parameters
+//	ctx (context.Context) : cancellation and deadline for the operation
+//	*UpsertAllStruct (Struct) :
+//		Data([] interfaces{}]) : the object which has to be inserted
+//		Query(bson Object) : Criteria as per the update should execute
+// Output Parameters
+//	records(*WriteResult) : returns matched, modified counts
+//	error : if it was error then return error else nil
+func (conn *Connection) UpsertAllCtx(ctx context.Context, upsertAllStruct *UpsertAllStruct) (*WriteResult, error) {
+	return conn.C(conn.Collection).UpsertAllCtx(ctx, upsertAllStruct)
 }
 
 // UpsertAllAsync : Function Upserts record into the collection if not found else updates
@@ -242,48 +237,41 @@ func (conn *Connection) UpsertAll(upsertAllStruct *UpsertAllStruct) (*mgo.Change
 //	callback(data, error) : sends data, error to channel
 
 func (conn *Connection) UpsertAllAsync(upsertAllStruct *UpsertAllStruct, callback chan *Callback) {
-	records, err := conn.UpsertAll(upsertAllStruct)
-	cb := new(Callback)
-	cb.Data = records
-	cb.Error = err
-	callback <- cb
+	conn.C(conn.Collection).UpsertAllAsync(upsertAllStruct, callback)
 }
 
-//	FindByID : Function FindByID finds and returns record by Hexadecimal ID
+//	FindByID : Function FindByID finds and returns record by ID
 //	Input Parameters :
 // 		*FindByIDStruct (Struct) :
-// 			Id(string) : The record id whose details have to be updated
+// 			Id(interface{}) : The record id (Hexadecimal ObjectId, StringID, int, UUID, etc) whose details have to be updated
 //	Output :
 //		record(interface{}) : Returns the mongo Object
-//		error : Return error object if found
+//		error : ErrorNotFound if there is no record with the ID, else error if it failed
 func (conn *Connection) FindByID(findByIDStruct *FindByIDStruct) (interface{}, error) {
-	var record interface{}
-	sessionCopy := conn.Session.Copy()
-	defer sessionCopy.Close()
-	collection := sessionCopy.DB(conn.Database).C(conn.Collection)
-	err := collection.FindId(bson.ObjectIdHex(findByIDStruct.Id)).Select(findByIDStruct.Fields).One(&record)
-	if err != nil {
-		log.Println(err)
-		if err.Error() == MongoErrorNotFound.Error() {
-			err = nil
-		}
-	}
-	return record, err
+	return conn.C(conn.Collection).FindByID(findByIDStruct)
+}
+
+//	FindByIDCtx : Function finds and returns record by ID, aborting when ctx is done
+//	Input Parameters :
+// 		ctx (context.Context) : cancellation and deadline for the operation
+// 		*FindByIDStruct (Struct) :
+// 			Id(interface{}) : The record id (Hexadecimal ObjectId, StringID, int, UUID, etc) whose details have to be updated
+//	Output :
+//		record(interface{}) : Returns the mongo Object
+//		error : ErrorNotFound if there is no record with the ID, else error if it failed
+func (conn *Connection) FindByIDCtx(ctx context.Context, findByIDStruct *FindByIDStruct) (interface{}, error) {
+	return conn.C(conn.Collection).FindByIDCtx(ctx, findByIDStruct)
 }
 
-//	FindByIDAsync : Function FindByIDAsync finds and returns record by Hexadecimal ID
+//	FindByIDAsync : Function FindByIDAsync finds and returns record by ID
 //	Input Parameters :
 // 		*FindByIDStruct (Struct) :
-// 			Id(string) : The record id whose details have to be updated
+// 			Id(interface{}) : The record id (Hexadecimal ObjectId, StringID, int, UUID, etc) whose details have to be updated
 //			callback (channel) : which returns data to goroutine
 //	Output :
 //		callback(data, error) : sends data, error to channel
 func (conn *Connection) FindByIDAsync(findByIDStruct *FindByIDStruct, callback chan *Callback) {
-	records, err := conn.FindByID(findByIDStruct)
-	cb := new(Callback)
-	cb.Data = records
-	cb.Error = err
-	callback <- cb
+	conn.C(conn.Collection).FindByIDAsync(findByIDStruct, callback)
 }
 
 // Find : Function finds the record into the collection according to the query/criteria
@@ -295,36 +283,20 @@ func (conn *Connection) FindByIDAsync(findByIDStruct *FindByIDStruct, callback c
 // 		records([]interface{]}) : Return the result mapped as interface
 // 		error(error) : if it was error then return error else nil
 func (conn *Connection) Find(findStruct *FindStruct) ([]interface{}, error) {
+	return conn.C(conn.Collection).Find(findStruct)
+}
 
-	var records []interface{}
-	var err error
-
-	sessionCopy := conn.Session.Copy()
-	defer sessionCopy.Close()
-
-	collection := sessionCopy.DB(conn.Database).C(conn.Collection)
-
-	limit, isLimit := findStruct.Options["limit"]
-	skip, isSkip := findStruct.Options["isSkip"]
-
-	if isLimit && isSkip {
-		err = collection.Find(findStruct.Query).Select(findStruct.Fields).Skip(skip).Limit(limit).All(&records)
-	} else if isLimit {
-		err = collection.Find(findStruct.Query).Select(findStruct.Fields).Limit(limit).All(&records)
-	} else if isSkip {
-		err = collection.Find(findStruct.Query).Select(findStruct.Fields).Skip(skip).All(&records)
-	} else {
-		err = collection.Find(findStruct.Query).Select(findStruct.Fields).All(&records)
-	}
-
-	if err != nil {
-		log.Println(err)
-		if err.Error() == MongoErrorNotFound.Error() {
-			err = nil
-		}
-	}
-
-	return records, err
+// FindCtx : Function finds the record into the collection according to the query/criteria, aborting when ctx is done
+// Input Parameters
+//		ctx (context.Context) : cancellation and deadline for the operation
+//		*FindStruct (Struct) :
+// 			Query(bson Object) : Criteria as per the update should execute
+//			Options(map[string]int) : optional things like, limit, skip, etc
+// Output Parameters
+// 		records([]interface{]}) : Return the result mapped as interface
+// 		error(error) : if it was error then return error else nil
+func (conn *Connection) FindCtx(ctx context.Context, findStruct *FindStruct) ([]interface{}, error) {
+	return conn.C(conn.Collection).FindCtx(ctx, findStruct)
 }
 
 // FindAsync : Function finds the record into the collection according to the query/criteria
@@ -336,11 +308,7 @@ func (conn *Connection) Find(findStruct *FindStruct) ([]interface{}, error) {
 // Output Parameters
 // 		callback(data, error) : returns data, error to channel
 func (conn *Connection) FindAsync(findStruct *FindStruct, callback chan *Callback) {
-	records, err := conn.Find(findStruct)
-	cb := new(Callback)
-	cb.Data = records
-	cb.Error = err
-	callback <- cb
+	conn.C(conn.Collection).FindAsync(findStruct, callback)
 }
 
 // FindAll : Function finds all the records into the collection
@@ -350,23 +318,18 @@ func (conn *Connection) FindAsync(findStruct *FindStruct, callback chan *Callbac
 // 		records([]interface{}) : all the records present in database
 // 		error : if it was error then return error else nil
 func (conn *Connection) FindAll(findAllStruct *FindAllStruct) ([]interface{}, error) {
+	return conn.C(conn.Collection).FindAll(findAllStruct)
+}
 
-	var records []interface{}
-
-	sessionCopy := conn.Session.Copy()
-	defer sessionCopy.Close()
-
-	collection := sessionCopy.DB(conn.Database).C(conn.Collection)
-
-	err := collection.Find(nil).Select(findAllStruct.Fields).All(&records)
-	if err != nil {
-		log.Println(err)
-		if err.Error() == MongoErrorNotFound.Error() {
-			err = nil
-		}
-	}
-
-	return records, err
+// FindAllCtx : Function finds all the records into the collection, aborting when ctx is done
+// Input Parameters
+//		ctx (context.Context) : cancellation and deadline for the operation
+//		*FindAllStruct (Struct) :
+// Output Parameters
+// 		records([]interface{}) : all the records present in database
+// 		error : if it was error then return error else nil
+func (conn *Connection) FindAllCtx(ctx context.Context, findAllStruct *FindAllStruct) ([]interface{}, error) {
+	return conn.C(conn.Collection).FindAllCtx(ctx, findAllStruct)
 }
 
 // FindAllAsync : Function finds all the records into the collection
@@ -376,11 +339,389 @@ func (conn *Connection) FindAll(findAllStruct *FindAllStruct) ([]interface{}, er
 // Output Parameters
 // 		callback(data, error) : returns data, error to channel
 func (conn *Connection) FindAllAsync(findAllStruct *FindAllStruct, callback chan *Callback) {
-	records, err := conn.FindAll(findAllStruct)
-	cb := new(Callback)
-	cb.Data = records
-	cb.Error = err
-	callback <- cb
+	conn.C(conn.Collection).FindAllAsync(findAllStruct, callback)
+}
+
+// FindCursor : Function returns a Cursor over the records matching the query/criteria, see Collection.FindCursor
+// Input Parameters
+//		*FindStruct (Struct) :
+// 			Query(bson Object) : Criteria as per the update should execute
+//			FindOptions : sort, skip, limit, batch size, etc
+// Output Parameters
+// 		*Cursor : cursor positioned before the first record
+// 		error : if it was error then return error else nil
+func (conn *Connection) FindCursor(findStruct *FindStruct) (*Cursor, error) {
+	return conn.C(conn.Collection).FindCursor(findStruct)
+}
+
+// FindCursorCtx : Function returns a Cursor over the records matching the query/criteria, reading them with ctx
+// Input Parameters
+//		ctx (context.Context) : cancellation and deadline for the query and the reads of the cursor
+//		*FindStruct (Struct) :
+// 			Query(bson Object) : Criteria as per the update should execute
+//			FindOptions : sort, skip, limit, batch size, etc
+// Output Parameters
+// 		*Cursor : cursor positioned before the first record
+// 		error : if it was error then return error else nil
+func (conn *Connection) FindCursorCtx(ctx context.Context, findStruct *FindStruct) (*Cursor, error) {
+	return conn.C(conn.Collection).FindCursorCtx(ctx, findStruct)
+}
+
+// FindStream : Function sends the records matching the query/criteria on callback one at a time, see Collection.FindStream
+// Input Parameters
+//		ctx (context.Context) : cancellation and deadline for the whole stream
+//		*FindStruct (Struct) :
+// 			Query(bson Object) : Criteria as per the update should execute
+//		callback (channel) : which returns data to goroutine
+// Output Parameters
+// 		callback(data, error) : sends every record, then the error if there is one, to channel
+func (conn *Connection) FindStream(ctx context.Context, findStruct *FindStruct, callback chan *Callback) {
+	conn.C(conn.Collection).FindStream(ctx, findStruct, callback)
+}
+
+// FindPage : Function returns a page of the records matching the query/criteria, see Collection.FindPage
+// Input Parameters
+//		*PageStruct (Struct) :
+// 			Query(bson Object) : Criteria as per the find should execute
+//			Sort(string) : key of the pages, "-" for descending, _id when empty
+//			Limit(int) : records per page, DefaultPageSize when 0
+//			Token(string) : NextToken of the previous page, empty for the first page
+// Output Parameters
+// 		*Page : the records and the token of the next page
+// 		error : ErrorValidation if the token is invalid or was issued for another Sort, else error if it failed
+func (conn *Connection) FindPage(pageStruct *PageStruct) (*Page, error) {
+	return conn.C(conn.Collection).FindPage(pageStruct)
+}
+
+// FindPageCtx : Function returns a page of the records matching the query/criteria, aborting when ctx is done
+// Input Parameters
+//		ctx (context.Context) : cancellation and deadline for the operation
+//		*PageStruct (Struct) :
+// 			Query(bson Object) : Criteria as per the find should execute
+//			Token(string) : NextToken of the previous page, empty for the first page
+// Output Parameters
+// 		*Page : the records and the token of the next page
+// 		error : ErrorValidation if the token is invalid or was issued for another Sort, else error if it failed
+func (conn *Connection) FindPageCtx(ctx context.Context, pageStruct *PageStruct) (*Page, error) {
+	return conn.C(conn.Collection).FindPageCtx(ctx, pageStruct)
+}
+
+// FindPageAsync : Function returns a page of the records matching the query/criteria
+// Input Parameters
+//		*PageStruct (Struct) :
+// 			Query(bson Object) : Criteria as per the find should execute
+//			Token(string) : NextToken of the previous page, empty for the first page
+//			callback (channel) : which returns data to goroutine
+// Output Parameters
+// 		callback(data, error) : returns the *Page, error to channel
+func (conn *Connection) FindPageAsync(pageStruct *PageStruct, callback chan *Callback) {
+	conn.C(conn.Collection).FindPageAsync(pageStruct, callback)
+}
+
+// Aggregate : Function runs the aggregation pipeline over the collection, see Collection.Aggregate
+// Input Parameters
+//		*AggregateStruct (Struct) :
+// 			Pipeline(Pipeline) : stages of the aggregation
+//			AllowDiskUse(bool) : let the large stages use temporary files
+//			BatchSize(int), MaxTime(time.Duration) : tuning of the query
+// Output Parameters
+// 		records([]interface{}) : the records out of the last stage
+// 		error : if it was error then return error else nil
+func (conn *Connection) Aggregate(aggregateStruct *AggregateStruct) ([]interface{}, error) {
+	return conn.C(conn.Collection).Aggregate(aggregateStruct)
+}
+
+// AggregateCtx : Function runs the aggregation pipeline over the collection, aborting when ctx is done
+// Input Parameters
+//		ctx (context.Context) : cancellation and deadline for the operation
+//		*AggregateStruct (Struct) :
+// 			Pipeline(Pipeline) : stages of the aggregation
+//			AllowDiskUse(bool) : let the large stages use temporary files
+//			BatchSize(int), MaxTime(time.Duration) : tuning of the query
+// Output Parameters
+// 		records([]interface{}) : the records out of the last stage
+// 		error : if it was error then return error else nil
+func (conn *Connection) AggregateCtx(ctx context.Context, aggregateStruct *AggregateStruct) ([]interface{}, error) {
+	return conn.C(conn.Collection).AggregateCtx(ctx, aggregateStruct)
+}
+
+// AggregateAsync : Function runs the aggregation pipeline over the collection
+// Input Parameters
+//		*AggregateStruct (Struct) :
+// 			Pipeline(Pipeline) : stages of the aggregation
+//			callback (channel) : which returns data to goroutine
+// Output Parameters
+// 		callback(data, error) : returns the records, error to channel
+func (conn *Connection) AggregateAsync(aggregateStruct *AggregateStruct, callback chan *Callback) {
+	conn.C(conn.Collection).AggregateAsync(aggregateStruct, callback)
+}
+
+// AggregateCursor : Function returns a Cursor over the records output by the aggregation pipeline, see Collection.AggregateCursor
+// Input Parameters
+//		*AggregateStruct (Struct) :
+// 			Pipeline(Pipeline) : stages of the aggregation
+//			BatchSize(int) : records per round trip
+// Output Parameters
+// 		*Cursor : cursor positioned before the first record
+// 		error : if it was error then return error else nil
+func (conn *Connection) AggregateCursor(aggregateStruct *AggregateStruct) (*Cursor, error) {
+	return conn.C(conn.Collection).AggregateCursor(aggregateStruct)
+}
+
+// AggregateCursorCtx : Function returns a Cursor over the records output by the aggregation pipeline, reading them with ctx
+// Input Parameters
+//		ctx (context.Context) : cancellation and deadline for the aggregation and the reads of the cursor
+//		*AggregateStruct (Struct) :
+// 			Pipeline(Pipeline) : stages of the aggregation
+// Output Parameters
+// 		*Cursor : cursor positioned before the first record
+// 		error : if it was error then return error else nil
+func (conn *Connection) AggregateCursorCtx(ctx context.Context, aggregateStruct *AggregateStruct) (*Cursor, error) {
+	return conn.C(conn.Collection).AggregateCursorCtx(ctx, aggregateStruct)
+}
+
+// AggregateStream : Function sends the records output by the aggregation pipeline on callback one at a time, see Collection.AggregateStream
+// Input Parameters
+//		ctx (context.Context) : cancellation and deadline for the whole stream
+//		*AggregateStruct (Struct) :
+// 			Pipeline(Pipeline) : stages of the aggregation
+//		callback (channel) : which returns data to goroutine
+// Output Parameters
+// 		callback(data, error) : sends every record, then the error if there is one, to channel
+func (conn *Connection) AggregateStream(ctx context.Context, aggregateStruct *AggregateStruct, callback chan *Callback) {
+	conn.C(conn.Collection).AggregateStream(ctx, aggregateStruct, callback)
+}
+
+// Count : Function counts the records matching the query/criteria without reading them, see Collection.Count
+// Input Parameters
+//		*CountStruct (Struct) :
+// 			Query(bson Object) : Criteria as per the count should execute, every record when empty
+//			Estimated(bool) : count of the collection read from its metadata
+// Output Parameters
+// 		count(int) : number of matching records
+// 		error : ErrorValidation if Estimated is set with a Query, else error if it failed
+func (conn *Connection) Count(countStruct *CountStruct) (int, error) {
+	return conn.C(conn.Collection).Count(countStruct)
+}
+
+// CountCtx : Function counts the records matching the query/criteria without reading them, aborting when ctx is done
+// Input Parameters
+//		ctx (context.Context) : cancellation and deadline for the operation
+//		*CountStruct (Struct) :
+// 			Query(bson Object) : Criteria as per the count should execute, every record when empty
+//			Estimated(bool) : count of the collection read from its metadata
+// Output Parameters
+// 		count(int) : number of matching records
+// 		error : ErrorValidation if Estimated is set with a Query, else error if it failed
+func (conn *Connection) CountCtx(ctx context.Context, countStruct *CountStruct) (int, error) {
+	return conn.C(conn.Collection).CountCtx(ctx, countStruct)
+}
+
+// CountAsync : Function counts the records matching the query/criteria without reading them
+// Input Parameters
+//		*CountStruct (Struct) :
+// 			Query(bson Object) : Criteria as per the count should execute, every record when empty
+//			callback (channel) : which returns data to goroutine
+// Output Parameters
+// 		callback(data, error) : returns the count, error to channel
+func (conn *Connection) CountAsync(countStruct *CountStruct, callback chan *Callback) {
+	conn.C(conn.Collection).CountAsync(countStruct, callback)
+}
+
+// Distinct : Function returns the distinct values of a field among the matching records, see Collection.Distinct
+// Input Parameters
+//		*DistinctStruct (Struct) :
+// 			Field(string) : dotted path of the field
+// 			Query(bson Object) : Criteria as per the distinct should execute, every record when empty
+// Output Parameters
+// 		values([]interface{}) : the distinct values, in no particular order
+// 		error : ErrorValidation if Field is empty, else error if it failed
+func (conn *Connection) Distinct(distinctStruct *DistinctStruct) ([]interface{}, error) {
+	return conn.C(conn.Collection).Distinct(distinctStruct)
+}
+
+// DistinctCtx : Function returns the distinct values of a field among the matching records, aborting when ctx is done
+// Input Parameters
+//		ctx (context.Context) : cancellation and deadline for the operation
+//		*DistinctStruct (Struct) :
+// 			Field(string) : dotted path of the field
+// 			Query(bson Object) : Criteria as per the distinct should execute, every record when empty
+// Output Parameters
+// 		values([]interface{}) : the distinct values, in no particular order
+// 		error : ErrorValidation if Field is empty, else error if it failed
+func (conn *Connection) DistinctCtx(ctx context.Context, distinctStruct *DistinctStruct) ([]interface{}, error) {
+	return conn.C(conn.Collection).DistinctCtx(ctx, distinctStruct)
+}
+
+// DistinctAsync : Function returns the distinct values of a field among the matching records
+// Input Parameters
+//		*DistinctStruct (Struct) :
+// 			Field(string) : dotted path of the field
+//			callback (channel) : which returns data to goroutine
+// Output Parameters
+// 		callback(data, error) : returns the values, error to channel
+func (conn *Connection) DistinctAsync(distinctStruct *DistinctStruct, callback chan *Callback) {
+	conn.C(conn.Collection).DistinctAsync(distinctStruct, callback)
+}
+
+// Exists : Function reports if a record matches the query/criteria, see Collection.Exists
+// Input Parameters
+//		*ExistsStruct (Struct) :
+// 			Query(bson Object) : Criteria as per the find should execute
+// Output Parameters
+// 		exists(bool) : true if at least one record matches
+// 		error : if it was error then return error else nil
+func (conn *Connection) Exists(existsStruct *ExistsStruct) (bool, error) {
+	return conn.C(conn.Collection).Exists(existsStruct)
+}
+
+// ExistsCtx : Function reports if a record matches the query/criteria, aborting when ctx is done
+// Input Parameters
+//		ctx (context.Context) : cancellation and deadline for the operation
+//		*ExistsStruct (Struct) :
+// 			Query(bson Object) : Criteria as per the find should execute
+// Output Parameters
+// 		exists(bool) : true if at least one record matches
+// 		error : if it was error then return error else nil
+func (conn *Connection) ExistsCtx(ctx context.Context, existsStruct *ExistsStruct) (bool, error) {
+	return conn.C(conn.Collection).ExistsCtx(ctx, existsStruct)
+}
+
+// ExistsAsync : Function reports if a record matches the query/criteria
+// Input Parameters
+//		*ExistsStruct (Struct) :
+// 			Query(bson Object) : Criteria as per the find should execute
+//			callback (channel) : which returns data to goroutine
+// Output Parameters
+// 		callback(data, error) : returns the bool, error to channel
+func (conn *Connection) ExistsAsync(existsStruct *ExistsStruct, callback chan *Callback) {
+	conn.C(conn.Collection).ExistsAsync(existsStruct, callback)
+}
+
+// FindOneAndUpdate : Function updates the first record matching the query/criteria and returns it, see Collection.FindOneAndUpdate
+// Input Parameters
+//		*FindOneAndUpdateStruct (Struct) :
+// 			Query(bson Object) : Criteria as per the update should execute
+//			Data(interface{}) : update operators
+//			Sort([]string) : picks the record when several match
+//			Fields(bson Object) : fields of the returned record
+//			Upsert(bool) : insert a record when none matches
+//			ReturnNew(bool) : return the record as updated instead of as it was
+// Output Parameters
+// 		record(interface{}) : the record before or after the update, nil when Upsert inserted one and ReturnNew is false
+// 		error : ErrorNotFound if no record matched, ErrorValidation if Data has no update operators, else error if it failed
+func (conn *Connection) FindOneAndUpdate(findOneAndUpdateStruct *FindOneAndUpdateStruct) (interface{}, error) {
+	return conn.C(conn.Collection).FindOneAndUpdate(findOneAndUpdateStruct)
+}
+
+// FindOneAndUpdateCtx : Function updates the first record matching the query/criteria and returns it, aborting when ctx is done
+// Input Parameters
+//		ctx (context.Context) : cancellation and deadline for the operation
+//		*FindOneAndUpdateStruct (Struct) :
+// 			Query(bson Object) : Criteria as per the update should execute
+//			Data(interface{}) : update operators
+//			Sort([]string) : picks the record when several match
+//			Fields(bson Object) : fields of the returned record
+//			Upsert(bool) : insert a record when none matches
+//			ReturnNew(bool) : return the record as updated instead of as it was
+// Output Parameters
+// 		record(interface{}) : the record before or after the update, nil when Upsert inserted one and ReturnNew is false
+// 		error : ErrorNotFound if no record matched, ErrorValidation if Data has no update operators, else error if it failed
+func (conn *Connection) FindOneAndUpdateCtx(ctx context.Context, findOneAndUpdateStruct *FindOneAndUpdateStruct) (interface{}, error) {
+	return conn.C(conn.Collection).FindOneAndUpdateCtx(ctx, findOneAndUpdateStruct)
+}
+
+// FindOneAndUpdateAsync : Function updates the first record matching the query/criteria and returns it
+// Input Parameters
+//		*FindOneAndUpdateStruct (Struct) :
+// 			Query(bson Object) : Criteria as per the update should execute
+//			callback (channel) : which returns data to goroutine
+// Output Parameters
+// 		callback(data, error) : returns the record, error to channel
+func (conn *Connection) FindOneAndUpdateAsync(findOneAndUpdateStruct *FindOneAndUpdateStruct, callback chan *Callback) {
+	conn.C(conn.Collection).FindOneAndUpdateAsync(findOneAndUpdateStruct, callback)
+}
+
+// FindOneAndReplace : Function replaces the first record matching the query/criteria and returns it, see Collection.FindOneAndReplace
+// Input Parameters
+//		*FindOneAndReplaceStruct (Struct) :
+// 			Query(bson Object) : Criteria as per the replace should execute
+//			Data(interface{}) : the new record, without update operators
+//			Sort([]string) : picks the record when several match
+//			Fields(bson Object) : fields of the returned record
+//			Upsert(bool) : insert Data when no record matches
+//			ReturnNew(bool) : return the new record instead of the replaced one
+// Output Parameters
+// 		record(interface{}) : the record before or after the replace, nil when Upsert inserted one and ReturnNew is false
+// 		error : ErrorNotFound if no record matched, ErrorValidation if Data has update operators, else error if it failed
+func (conn *Connection) FindOneAndReplace(findOneAndReplaceStruct *FindOneAndReplaceStruct) (interface{}, error) {
+	return conn.C(conn.Collection).FindOneAndReplace(findOneAndReplaceStruct)
+}
+
+// FindOneAndReplaceCtx : Function replaces the first record matching the query/criteria and returns it, aborting when ctx is done
+// Input Parameters
+//		ctx (context.Context) : cancellation and deadline for the operation
+//		*FindOneAndReplaceStruct (Struct) :
+// 			Query(bson Object) : Criteria as per the replace should execute
+//			Data(interface{}) : the new record, without update operators
+//			Sort([]string) : picks the record when several match
+//			Fields(bson Object) : fields of the returned record
+//			Upsert(bool) : insert Data when no record matches
+//			ReturnNew(bool) : return the new record instead of the replaced one
+// Output Parameters
+// 		record(interface{}) : the record before or after the replace, nil when Upsert inserted one and ReturnNew is false
+// 		error : ErrorNotFound if no record matched, ErrorValidation if Data has update operators, else error if it failed
+func (conn *Connection) FindOneAndReplaceCtx(ctx context.Context, findOneAndReplaceStruct *FindOneAndReplaceStruct) (interface{}, error) {
+	return conn.C(conn.Collection).FindOneAndReplaceCtx(ctx, findOneAndReplaceStruct)
+}
+
+// FindOneAndReplaceAsync : Function replaces the first record matching the query/criteria and returns it
+// Input Parameters
+//		*FindOneAndReplaceStruct (Struct) :
+// 			Query(bson Object) : Criteria as per the replace should execute
+//			callback (channel) : which returns data to goroutine
+// Output Parameters
+// 		callback(data, error) : returns the record, error to channel
+func (conn *Connection) FindOneAndReplaceAsync(findOneAndReplaceStruct *FindOneAndReplaceStruct, callback chan *Callback) {
+	conn.C(conn.Collection).FindOneAndReplaceAsync(findOneAndReplaceStruct, callback)
+}
+
+// FindOneAndDelete : Function removes the first record matching the query/criteria and returns it, see Collection.FindOneAndDelete
+// Input Parameters
+//		*FindOneAndDeleteStruct (Struct) :
+// 			Query(bson Object) : Criteria as per the remove should execute
+//			Sort([]string) : picks the record when several match
+//			Fields(bson Object) : fields of the returned record
+// Output Parameters
+// 		record(interface{}) : the removed record
+// 		error : ErrorNotFound if no record matched, else error if it failed
+func (conn *Connection) FindOneAndDelete(findOneAndDeleteStruct *FindOneAndDeleteStruct) (interface{}, error) {
+	return conn.C(conn.Collection).FindOneAndDelete(findOneAndDeleteStruct)
+}
+
+// FindOneAndDeleteCtx : Function removes the first record matching the query/criteria and returns it, aborting when ctx is done
+// Input Parameters
+//		ctx (context.Context) : cancellation and deadline for the operation
+//		*FindOneAndDeleteStruct (Struct) :
+// 			Query(bson Object) : Criteria as per the remove should execute
+//			Sort([]string) : picks the record when several match
+//			Fields(bson Object) : fields of the returned record
+// Output Parameters
+// 		record(interface{}) : the removed record
+// 		error : ErrorNotFound if no record matched, else error if it failed
+func (conn *Connection) FindOneAndDeleteCtx(ctx context.Context, findOneAndDeleteStruct *FindOneAndDeleteStruct) (interface{}, error) {
+	return conn.C(conn.Collection).FindOneAndDeleteCtx(ctx, findOneAndDeleteStruct)
+}
+
+// FindOneAndDeleteAsync : Function removes the first record matching the query/criteria and returns it
+// Input Parameters
+//		*FindOneAndDeleteStruct (Struct) :
+// 			Query(bson Object) : Criteria as per the remove should execute
+//			callback (channel) : which returns data to goroutine
+// Output Parameters
+// 		callback(data, error) : returns the removed record, error to channel
+func (conn *Connection) FindOneAndDeleteAsync(findOneAndDeleteStruct *FindOneAndDeleteStruct, callback chan *Callback) {
+	conn.C(conn.Collection).FindOneAndDeleteAsync(findOneAndDeleteStruct, callback)
 }
 
 // Remove : Function removes the record from the collection as per criteria/query
@@ -388,20 +729,22 @@ func (conn *Connection) FindAllAsync(findAllStruct *FindAllStruct, callback chan
 //		*RemoveStruct (Struct) :
 // 			Query(bson Object) : Criteria as per the update should execute
 // Output Parameters
-// 		records(boolean) : returns true / false depending on output of operation
-// 		error : if it was error then return error else nil
-func (conn *Connection) Remove(removeStruct *RemoveStruct) error {
-	sessionCopy := conn.Session.Copy()
-	defer sessionCopy.Close()
-	collection := sessionCopy.DB(conn.Database).C(conn.Collection)
-	err := collection.Remove(removeStruct.Query)
-	if err != nil {
-		log.Println(err)
-		if err.Error() == MongoErrorNotFound.Error() {
-			err = nil
-		}
-	}
-	return err
+// 		result(*WriteResult) : returns matched, removed counts
+// 		error : ErrorNotFound if no record matched, else error if it failed, nil on success
+func (conn *Connection) Remove(removeStruct *RemoveStruct) (*WriteResult, error) {
+	return conn.C(conn.Collection).Remove(removeStruct)
+}
+
+// RemoveCtx : Function removes the record from the collection as per criteria/query, aborting when ctx is done
+// Input Parameters
+//		ctx (context.Context) : cancellation and deadline for the operation
+//		*RemoveStruct (Struct) :
+// 			Query(bson Object) : Criteria as per the update should execute
+// Output Parameters
+// 		result(*WriteResult) : returns matched, removed counts
+// 		error : ErrorNotFound if no record matched, else error if it failed, nil on success
+func (conn *Connection) RemoveCtx(ctx context.Context, removeStruct *RemoveStruct) (*WriteResult, error) {
+	return conn.C(conn.Collection).RemoveCtx(ctx, removeStruct)
 }
 
 // removeAsync : Function removes the record from the collection as per criteria/query
@@ -412,34 +755,28 @@ func (conn *Connection) Remove(removeStruct *RemoveStruct) error {
 // Output Parameters
 // 		callback(data, error) : returns data, error to channel
 func (conn *Connection) RemoveAsync(removeStruct *RemoveStruct, callback chan *Callback) {
-	err := conn.Remove(removeStruct)
-	cb := new(Callback)
-	cb.Data = nil
-	cb.Error = err
-	callback <- cb
+	conn.C(conn.Collection).RemoveAsync(removeStruct, callback)
 }
 
 // RemoveAll : Function removes all the record from the collection
 // Input Parameters
 //		*RemoveAllStruct (Struct) :
 // Output Parameters
-// 		records(*mgo.ChangeInfo) : returns matched modified removed count
+// 		records(*WriteResult) : returns matched, removed counts
 // 		error : if it was error then return error else nil
-func (conn *Connection) RemoveAll(removeAllStruct *RemoveAllStruct) (*mgo.ChangeInfo, error) {
-
-	sessionCopy := conn.Session.Copy()
-	defer sessionCopy.Close()
-	collection := sessionCopy.DB(conn.Database).C(conn.Collection)
-	records, err := collection.RemoveAll(nil)
-	if err != nil {
-		log.Println(err)
-		records = nil
-		if err.Error() == MongoErrorNotFound.Error() {
-			err = nil
-		}
-	}
+func (conn *Connection) RemoveAll(removeAllStruct *RemoveAllStruct) (*WriteResult, error) {
+	return conn.C(conn.Collection).RemoveAll(removeAllStruct)
+}
 
-	return records, err
+// RemoveAllCtx : Function removes all the record from the collection, aborting when ctx is done
+// Input Parameters
+//		ctx (context.Context) : cancellation and deadline for the operation
+//		*RemoveAllStruct (Struct) :
+// Output Parameters
+// 		records(*WriteResult) : returns matched, removed counts
+// 		error : if it was error then return error else nil
+func (conn *Connection) RemoveAllCtx(ctx context.Context, removeAllStruct *RemoveAllStruct) (*WriteResult, error) {
+	return conn.C(conn.Collection).RemoveAllCtx(ctx, removeAllStruct)
 }
 
 // RemoveAllAsync : Function removes all the record from the collection asynchronously
@@ -449,25 +786,30 @@ func (conn *Connection) RemoveAll(removeAllStruct *RemoveAllStruct) (*mgo.Change
 // Output Parameters
 // 		callback(data, error) : returns data, error to channel
 func (conn *Connection) RemoveAllAsync(removeAllStruct *RemoveAllStruct, callback chan *Callback) {
-	records, err := conn.RemoveAll(removeAllStruct)
-	cb := new(Callback)
-	cb.Data = records
-	cb.Error = err
-	callback <- cb
+	conn.C(conn.Collection).RemoveAllAsync(removeAllStruct, callback)
 }
 
 func Close(conn *Connection) error {
-	conn.Session.Close()
-	return nil
+	//a failed Connect returns a nil Connection, which is commonly closed by a defer
+	if conn == nil {
+		return nil
+	}
+	conn.StopMonitor()
+	if conn.Store != nil {
+		return conn.Store.Close()
+	}
+	if conn.Client == nil {
+		return nil
+	}
+	return conn.Client.Disconnect(context.Background())
 }
 
 //Database factory
-//Return a DB for general purpose
-func Init(driver string) (DB, error) {
-	switch driver {
-	case MONGODB:
-		return new(MongoDB), nil
-	default:
+//Return a DB for general purpose, driver being MONGODB or any name given to Register
+func Init(name string) (DB, error) {
+	factory, ok := driver(name)
+	if !ok {
 		return nil, ErrorInvalidDriver
 	}
+	return factory(), nil
 }
@@ -1,6 +1,8 @@
 package gomongo
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log"
 	"os/exec"
@@ -39,7 +41,7 @@ func ConnectForTest() (*Connection, error) {
 
 	//mock the database first
 	MockTestDB()
-	config := Config{"mongodb", TestDBHosts, TestDatabase, TestUserName, TestPassword}
+	config := Config{DbType: MONGODB, Hosts: TestDBHosts, Database: TestDatabase, Username: TestUserName, Password: TestPassword}
 
 	db, err := Init(MONGODB)
 
@@ -56,11 +58,20 @@ func ConnectForTest() (*Connection, error) {
 	return session, err
 }
 
+//upserts a record with the given id so tests don't depend on each other
+func seedUser(t *testing.T, conn *Connection, id string, data bson.M) {
+	upsertStruct := new(UpsertStruct)
+	upsertStruct.Id = id
+	upsertStruct.Data = bson.M{"$set": data}
+	_, err := conn.C("users").Upsert(upsertStruct)
+	assert.Nil(t, err)
+}
+
 func TestInit(t *testing.T) {
 	db, err := Init(MONGODB)
 	assert.Nil(t, err)
 
-	config := Config{"mongodb", TestDBHosts, TestDatabase, TestUserName, TestPassword}
+	config := Config{DbType: MONGODB, Hosts: TestDBHosts, Database: TestDatabase, Username: TestUserName, Password: TestPassword}
 	_, err = db.Connect(&config)
 	assert.Nil(t, err)
 }
@@ -80,8 +91,11 @@ func TestInsert(t *testing.T) {
 	conn.Collection = "users"
 	insertStruct := new(InsertStruct)
 	insertStruct.Data = user
-	err = conn.Insert(insertStruct)
+	result, err := conn.Insert(insertStruct)
 	assert.Nil(t, err)
+	if assert.Len(t, result.InsertedIds, 1) {
+		assert.IsType(t, bson.NewObjectId(), result.InsertedIds[0], "Person has no _id, an ObjectId is given to it")
+	}
 }
 
 func TestInsertAsync(t *testing.T) {
@@ -137,11 +151,89 @@ func TestFindAllAsync(t *testing.T) {
 	assert.Nil(t, output.Error)
 }
 
+func TestFindCursor(t *testing.T) {
+	conn, err := ConnectForTest()
+	defer Close(conn)
+	assert.Nil(t, err)
+
+	conn.Collection = "users"
+	cursor, err := conn.FindCursor(&FindStruct{FindOptions: FindOptions{BatchSize: 2, Limit: 5}})
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer cursor.Close()
+	count := 0
+	for cursor.Next() {
+		var person Person
+		assert.Nil(t, cursor.Decode(&person))
+		count++
+	}
+	assert.Nil(t, cursor.Err())
+	assert.LessOrEqual(t, count, 5)
+}
+
+func TestCount(t *testing.T) {
+	conn, err := ConnectForTest()
+	defer Close(conn)
+	assert.Nil(t, err)
+
+	seedUser(t, conn, "5b28da94a34bd180f5ab0f5a", bson.M{"firstname": "AmulyaXXX", "lastname": "Kashyap"})
+
+	conn.Collection = "users"
+	count, err := conn.Count(&CountStruct{Query: bson.M{"lastname": "Kashyap"}})
+	assert.Nil(t, err)
+	assert.GreaterOrEqual(t, count, 1)
+	exists, err := conn.Exists(&ExistsStruct{Query: bson.M{"lastname": "Kashyap"}})
+	assert.Nil(t, err)
+	assert.True(t, exists)
+	names, err := conn.Distinct(&DistinctStruct{Field: "lastname"})
+	assert.Nil(t, err)
+	assert.Contains(t, names, "Kashyap")
+}
+
+func TestAggregate(t *testing.T) {
+	conn, err := ConnectForTest()
+	defer Close(conn)
+	assert.Nil(t, err)
+
+	seedUser(t, conn, "5b28da94a34bd180f5ab0f5a", bson.M{"firstname": "AmulyaXXX", "lastname": "Kashyap"})
+
+	conn.Collection = "users"
+	records, err := conn.Aggregate(&AggregateStruct{
+		Pipeline:     Pipeline{}.Match(bson.M{"lastname": "Kashyap"}).Group("$lastname", bson.M{"count": bson.M{"$sum": 1}}),
+		AllowDiskUse: true,
+	})
+	assert.Nil(t, err)
+	if assert.Len(t, records, 1) {
+		assert.Equal(t, "Kashyap", records[0].(bson.M)["_id"])
+	}
+}
+
+func TestFindOneAndUpdate(t *testing.T) {
+	conn, err := ConnectForTest()
+	defer Close(conn)
+	assert.Nil(t, err)
+
+	seedUser(t, conn, "5b28da94a34bd180f5ab0f5a", bson.M{"firstname": "AmulyaXXX", "lastname": "Kashyap"})
+
+	conn.Collection = "users"
+	record, err := conn.FindOneAndUpdate(&FindOneAndUpdateStruct{
+		Query:     bson.M{"_id": bson.ObjectIdHex("5b28da94a34bd180f5ab0f5a")},
+		Data:      bson.M{"$set": bson.M{"firstname": "Amulya"}},
+		Fields:    bson.M{"firstname": 1},
+		ReturnNew: true,
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, "Amulya", record.(bson.M)["firstname"])
+}
+
 func TestFindById(t *testing.T) {
 	conn, err := ConnectForTest()
 	defer Close(conn)
 	assert.Nil(t, err)
 
+	seedUser(t, conn, "5b28da94a34bd180f5ab0f5a", bson.M{"firstname": "AmulyaXXX", "lastname": "Kashyap"})
+
 	findByIDStruct := new(FindByIDStruct)
 	conn.Collection = "users"
 	findByIDStruct.Id = "5b28da94a34bd180f5ab0f5a"
@@ -155,6 +247,8 @@ func TestFindByIdAsync(t *testing.T) {
 	defer Close(conn)
 	assert.Nil(t, err)
 
+	seedUser(t, conn, "5b28da94a34bd180f5ab0f5a", bson.M{"firstname": "AmulyaXXX", "lastname": "Kashyap"})
+
 	outputCh := make(chan *Callback)
 	findByIDStruct := new(FindByIDStruct)
 	conn.Collection = "users"
@@ -258,12 +352,14 @@ func TestUpdate(t *testing.T) {
 	defer Close(conn)
 	assert.Nil(t, err)
 
+	seedUser(t, conn, "5b28da94a34bd180f5ab0f5a", bson.M{"firstname": "AmulyaXXX", "lastname": "Kashyap"})
+
 	updateStruct := new(UpdateStruct)
 	conn.Collection = "users"
 	updateStruct.Id = "5b28da94a34bd180f5ab0f5a"
 	updateStruct.Data = bson.M{"$set": bson.M{"firstname": "AmulyaXXX", "lastname": "Kashyap", "age": 26, "phone": "9559974779", "salary": "7854693210", "datetime": time.Now()}}
 
-	err = conn.Update(updateStruct)
+	_, err = conn.Update(updateStruct)
 	assert.Nil(t, err)
 }
 
@@ -272,12 +368,14 @@ func TestUpdateOne(t *testing.T) {
 	defer Close(conn)
 	assert.Nil(t, err)
 
-	updateStruct := new(UpdateOneStruct)
+	seedUser(t, conn, bson.NewObjectId().Hex(), bson.M{"firstname": "Amulya", "lastname": "Kashyap"})
+
+	var updateStruct UpdateOneStruct
 	conn.Collection = "users"
 	updateStruct.Query = bson.M{"firstname": "Amulya", "lastname": "Kashyap"}
 	updateStruct.Data = bson.M{"$set": bson.M{"firstname": "AmulyaXXX", "lastname": "KashyapXXX", "age": 26, "phone": "9559974779", "salary": "7854693210", "datetime": time.Now()}}
 
-	err = conn.UpdateOne(updateStruct)
+	_, err = conn.UpdateOne(updateStruct)
 	assert.Nil(t, err)
 }
 
@@ -286,6 +384,8 @@ func TestUpdateAsync(t *testing.T) {
 	defer Close(conn)
 	assert.Nil(t, err)
 
+	seedUser(t, conn, "5b28da94a34bd180f5ab0f5a", bson.M{"firstname": "AmulyaXXX", "lastname": "Kashyap"})
+
 	outputCh := make(chan *Callback)
 
 	updateStruct := new(UpdateStruct)
@@ -321,7 +421,6 @@ func TestUpdateAllAsync(t *testing.T) {
 	assert.Nil(t, err)
 	outputCh := make(chan *Callback)
 	var updateStructAll UpdateAllStruct
-	updateStructAll := new(UpdateAllStruct)
 	conn.Collection = "users"
 	updateStructAll.Query = bson.M{"_id": bson.ObjectIdHex("5b28da94a34bd180f5ab0f5a")}
 	updateStructAll.Data = bson.M{"$set": bson.M{"firstname": "AmulyaXXX", "lastname": "Kashyap", "age": 26, "phone": "9559974779", "salary": "7854693210", "datetime": time.Now()}}
@@ -351,11 +450,13 @@ func TestRemove(t *testing.T) {
 	defer Close(conn)
 	assert.Nil(t, err)
 
+	seedUser(t, conn, "5b28da94a34bd180f5ab0f67", bson.M{"firstname": "AmulyaRemove"})
+
 	removeStruct := new(RemoveStruct)
 	conn.Collection = "users"
 	removeStruct.Query = bson.M{"_id": bson.ObjectIdHex("5b28da94a34bd180f5ab0f67")}
 
-	err = conn.Remove(removeStruct)
+	_, err = conn.Remove(removeStruct)
 	assert.Nil(t, err)
 }
 
@@ -364,6 +465,8 @@ func TestRemoveAsync(t *testing.T) {
 	defer Close(conn)
 	assert.Nil(t, err)
 
+	seedUser(t, conn, "5b28da94a34bd180f5ab0f63", bson.M{"firstname": "AmulyaRemove"})
+
 	outputCh := make(chan *Callback)
 
 	removeStruct := new(RemoveStruct)
@@ -399,3 +502,115 @@ func TestRemoveAllAsync(t *testing.T) {
 	output := <-outputCh
 	assert.Nil(t, output.Error)
 }
+
+func TestFindCtxCancelled(t *testing.T) {
+	conn, err := ConnectForTest()
+	defer Close(conn)
+	assert.Nil(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	findStruct := new(FindStruct)
+	conn.Collection = "users"
+	findStruct.Query = bson.M{"firstname": "AmulyaXXX"}
+	_, err = conn.FindCtx(ctx, findStruct)
+	assert.True(t, errors.Is(err, context.Canceled))
+}
+
+func TestInsertCtxDeadline(t *testing.T) {
+	conn, err := ConnectForTest()
+	defer Close(conn)
+	assert.Nil(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Nanosecond)
+	defer cancel()
+	time.Sleep(time.Millisecond)
+
+	insertStruct := new(InsertStruct)
+	insertStruct.Data = bson.M{"firstname": "AmulyaDeadline"}
+	conn.Collection = "users"
+	_, err = conn.InsertCtx(ctx, insertStruct)
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+	assert.True(t, errors.Is(err, ErrorTimeout))
+}
+
+func TestCollectionHandle(t *testing.T) {
+	conn, err := ConnectForTest()
+	defer Close(conn)
+	assert.Nil(t, err)
+
+	users := conn.C("users")
+	assert.True(t, users == conn.C("users"))
+	assert.Equal(t, "users", users.Name)
+
+	insertStruct := new(InsertStruct)
+	insertStruct.Data = bson.M{"firstname": "AmulyaHandle"}
+
+	outputCh := make(chan *Callback)
+	go users.InsertAsync(insertStruct, outputCh)
+	go conn.C("users_archive").InsertAsync(insertStruct, outputCh)
+	assert.Nil(t, (<-outputCh).Error)
+	assert.Nil(t, (<-outputCh).Error)
+}
+
+func TestNotFound(t *testing.T) {
+	conn, err := ConnectForTest()
+	defer Close(conn)
+	assert.Nil(t, err)
+
+	missingId := bson.NewObjectId().Hex()
+	users := conn.C("users")
+
+	_, err = users.FindByID(&FindByIDStruct{Id: missingId})
+	assert.True(t, errors.Is(err, ErrorNotFound))
+
+	_, err = users.Update(&UpdateStruct{Id: missingId, Data: bson.M{"$set": bson.M{"age": 27}}})
+	assert.True(t, errors.Is(err, ErrorNotFound))
+
+	_, err = users.Remove(&RemoveStruct{Query: bson.M{"_id": bson.ObjectIdHex(missingId)}})
+	assert.True(t, errors.Is(err, ErrorNotFound))
+}
+
+func TestDuplicateKey(t *testing.T) {
+	conn, err := ConnectForTest()
+	defer Close(conn)
+	assert.Nil(t, err)
+
+	insertStruct := new(InsertStruct)
+	insertStruct.Data = bson.M{"_id": bson.NewObjectId(), "firstname": "AmulyaDuplicate"}
+	users := conn.C("users")
+	_, err = users.Insert(insertStruct)
+	assert.Nil(t, err)
+
+	_, err = users.Insert(insertStruct)
+	assert.True(t, errors.Is(err, ErrorDuplicateKey))
+}
+
+func TestMalformedID(t *testing.T) {
+	conn, err := ConnectForTest()
+	defer Close(conn)
+	assert.Nil(t, err)
+
+	users := conn.C("users")
+	_, err = users.FindByID(&FindByIDStruct{Id: "not-an-object-id"})
+	assert.True(t, errors.Is(err, ErrorInvalidID))
+
+	_, err = users.Update(&UpdateStruct{Id: "5b28da94", Data: bson.M{"$set": bson.M{"age": 27}}})
+	assert.True(t, errors.Is(err, ErrorInvalidID))
+}
+
+func TestNonObjectID(t *testing.T) {
+	conn, err := ConnectForTest()
+	defer Close(conn)
+	assert.Nil(t, err)
+
+	users := conn.C("users")
+	for _, id := range []interface{}{StringID("sku-42"), 42, UUID([16]byte{0x12, 0x3e, 0x45, 0x67})} {
+		_, err = users.Upsert(&UpsertStruct{Id: id, Data: bson.M{"$set": bson.M{"firstname": "AmulyaCustomID"}}})
+		assert.Nil(t, err)
+
+		_, err = users.FindByID(&FindByIDStruct{Id: id})
+		assert.Nil(t, err)
+	}
+}
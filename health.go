@@ -0,0 +1,215 @@
+package gomongo
+
+import (
+	"context"
+	"log"
+	"sync/atomic"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Ping : Function checks the connection with a round trip to the server
+// Output Parameters
+//		error : nil if the server answered, else an *Error (ErrorNetwork, ErrorTimeout, ErrorAuth kinds)
+func (conn *Connection) Ping() error {
+	return conn.PingCtx(context.Background())
+}
+
+// PingCtx : Function is the context aware variant of Ping
+// Input Parameters
+//		ctx (context.Context) : cancellation and deadline of the check
+// Output Parameters
+//		error : nil if the server answered, else an *Error (ErrorNetwork, ErrorTimeout, ErrorAuth kinds)
+func (conn *Connection) PingCtx(ctx context.Context) error {
+	var err error
+	if conn.Store != nil {
+		err = conn.Store.Ping(ctx)
+	} else {
+		err = conn.run(ctx, func(client *mongo.Client) error {
+			return client.Ping(ctx, nil)
+		})
+	}
+	if err != nil {
+		log.Println(err)
+		return newError("Ping", "", err)
+	}
+	return nil
+}
+
+// Healthy : Function reports if the connection can serve requests, i.e. for a readiness probe
+// While a Monitor runs the result of its last check is returned without touching the network,
+// otherwise the server is pinged
+// Output Parameters
+//		bool : true if the connection is healthy
+func (conn *Connection) Healthy() bool {
+	conn.monitorMu.Lock()
+	m := conn.monitor
+	conn.monitorMu.Unlock()
+	if m != nil {
+		return m.healthy.Load()
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), DefaultPingTimeout)
+	defer cancel()
+	return conn.PingCtx(ctx) == nil
+}
+
+// Monitor : Function starts checking the connection in the background
+// The driver drops the connections to a server which went away (i.e. a primary which stepped down)
+// and finds the current servers by itself, the monitor reports the outages and the recoveries.
+// Failed checks are retried with an exponential backoff until the connection is back.
+// A running monitor is replaced, it's stopped by StopMonitor and Close
+// Input Parameters
+//		config (*MonitorConfig) : intervals and lifecycle callbacks, defaults are used when nil
+func (conn *Connection) Monitor(config *MonitorConfig) {
+	m := newMonitor(conn, config)
+
+	conn.monitorMu.Lock()
+	previous := conn.monitor
+	conn.monitor = m
+	conn.monitorMu.Unlock()
+
+	if previous != nil {
+		previous.stop()
+	}
+	go m.run()
+}
+
+// StopMonitor : Function stops the monitor started by Monitor and waits for it to exit, nothing to do on a nil Connection
+func (conn *Connection) StopMonitor() {
+	if conn == nil {
+		return
+	}
+	conn.monitorMu.Lock()
+	m := conn.monitor
+	conn.monitor = nil
+	conn.monitorMu.Unlock()
+
+	if m != nil {
+		m.stop()
+	}
+}
+
+// monitor checks a connection periodically, see Connection.Monitor
+type monitor struct {
+	conn    *Connection
+	config  MonitorConfig
+	ping    func(timeout time.Duration) error //health check, replaced in tests
+	healthy atomic.Bool
+	events  chan func() //callbacks waiting for the dispatch goroutine
+	quit    chan struct{}
+	done    chan struct{}
+}
+
+// newMonitor : Function builds the monitor of conn, applying the defaults to config
+func newMonitor(conn *Connection, config *MonitorConfig) *monitor {
+	m := &monitor{
+		conn:   conn,
+		events: make(chan func(), 8),
+		quit:   make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+	if config != nil {
+		m.config = *config
+	}
+	if m.config.Interval <= 0 {
+		m.config.Interval = DefaultMonitorInterval
+	}
+	if m.config.PingTimeout <= 0 {
+		m.config.PingTimeout = DefaultPingTimeout
+	}
+	if m.config.MinBackoff <= 0 {
+		m.config.MinBackoff = DefaultMinBackoff
+	}
+	if m.config.MaxBackoff < m.config.MinBackoff {
+		m.config.MaxBackoff = DefaultMaxBackoff
+		if m.config.MaxBackoff < m.config.MinBackoff {
+			m.config.MaxBackoff = m.config.MinBackoff
+		}
+	}
+
+	m.ping = func(timeout time.Duration) error {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		return conn.PingCtx(ctx)
+	}
+	return m
+}
+
+// run : Function checks the connection until the monitor is stopped
+func (m *monitor) run() {
+	defer close(m.done)
+	defer close(m.events)
+	go m.dispatch()
+
+	connected, down := false, false
+	backoff := m.config.MinBackoff
+	for {
+		wait := m.config.Interval
+		if err := m.ping(m.config.PingTimeout); err == nil {
+			m.healthy.Store(true)
+			notified := true
+			switch {
+			case down && m.config.OnReconnected != nil:
+				notified = m.notify(func() { m.config.OnReconnected(m.conn) })
+			case !connected && m.config.OnConnected != nil:
+				notified = m.notify(func() { m.config.OnConnected(m.conn) })
+			}
+			if !notified {
+				return
+			}
+			connected, down = true, false
+			backoff = m.config.MinBackoff
+		} else {
+			m.healthy.Store(false)
+			if !down && m.config.OnDisconnected != nil {
+				if !m.notify(func() { m.config.OnDisconnected(m.conn, err) }) {
+					return
+				}
+			}
+			down = true
+			wait = backoff
+			if backoff *= 2; backoff > m.config.MaxBackoff {
+				backoff = m.config.MaxBackoff
+			}
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-timer.C:
+		case <-m.quit:
+			timer.Stop()
+			return
+		}
+	}
+}
+
+// notify : Function queues a callback for the dispatch goroutine
+// Output Parameters
+//		bool : false if the monitor was stopped while the queue was full
+func (m *monitor) notify(event func()) bool {
+	select {
+	case m.events <- event:
+		return true
+	case <-m.quit:
+		return false
+	}
+}
+
+// dispatch : Function runs the queued callbacks in order, away from run so that a callback stopping the
+// monitor (i.e. calling Close) doesn't wait for itself, the callbacks queued after the stop are dropped
+func (m *monitor) dispatch() {
+	for event := range m.events {
+		select {
+		case <-m.quit:
+		default:
+			event()
+		}
+	}
+}
+
+// stop : Function stops the monitor and waits for the running check to finish
+func (m *monitor) stop() {
+	close(m.quit)
+	<-m.done
+}
@@ -0,0 +1,141 @@
+package gomongo
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPing(t *testing.T) {
+	config := Config{DbType: MONGODB, Hosts: TestDBHosts, Database: TestDatabase, Username: TestUserName, Password: TestPassword}
+	conn, err := ConnectMongo(&config)
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer Close(conn)
+
+	assert.Nil(t, conn.Ping())
+	assert.True(t, conn.Healthy())
+}
+
+func TestMonitor(t *testing.T) {
+	var mu sync.Mutex
+	var events []string
+	record := func(event string) {
+		mu.Lock()
+		defer mu.Unlock()
+		events = append(events, event)
+	}
+
+	conn := new(Connection)
+	m := newMonitor(conn, &MonitorConfig{
+		Interval:       time.Millisecond,
+		MinBackoff:     time.Millisecond,
+		MaxBackoff:     4 * time.Millisecond,
+		OnConnected:    func(*Connection) { record("connected") },
+		OnDisconnected: func(_ *Connection, err error) { record("disconnected") },
+		OnReconnected:  func(*Connection) { record("reconnected") },
+	})
+
+	//healthy, then three network failures, then healthy again
+	results := []error{nil, ErrorNetwork, ErrorNetwork, ErrorNetwork, nil}
+	checks := 0
+	finished := make(chan struct{})
+	m.ping = func(time.Duration) error {
+		checks++
+		if checks > len(results) {
+			if checks == len(results)+1 {
+				close(finished)
+			}
+			return nil
+		}
+		return results[checks-1]
+	}
+	conn.monitor = m
+
+	go m.run()
+	<-finished
+	assert.True(t, conn.Healthy())
+	//the callbacks run on their own goroutine, wait for them before stopping
+	assert.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(events) == 3
+	}, time.Second, time.Millisecond)
+	conn.StopMonitor()
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, []string{"connected", "disconnected", "reconnected"}, events)
+}
+
+func TestMonitorCloseFromCallback(t *testing.T) {
+	conn := new(Connection)
+	closed := make(chan error)
+	m := newMonitor(conn, &MonitorConfig{
+		MinBackoff: time.Millisecond,
+		OnDisconnected: func(conn *Connection, err error) {
+			//a readiness exporter shutting down on the first outage
+			closed <- Close(conn)
+		},
+	})
+	m.ping = func(time.Duration) error { return ErrorNetwork }
+	conn.monitor = m
+
+	go m.run()
+	select {
+	case err := <-closed:
+		assert.Nil(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("Close called from a callback never returned")
+	}
+	<-m.done
+	assert.Nil(t, conn.monitor)
+}
+
+func TestMonitorBackoff(t *testing.T) {
+	conn := new(Connection)
+	m := newMonitor(conn, &MonitorConfig{MinBackoff: time.Millisecond, MaxBackoff: 4 * time.Millisecond})
+
+	var waits []time.Duration
+	last := time.Now()
+	finished := make(chan struct{})
+	m.ping = func(time.Duration) error {
+		now := time.Now()
+		waits = append(waits, now.Sub(last))
+		last = now
+		if len(waits) == 6 {
+			close(finished)
+		}
+		return ErrorAuth
+	}
+	conn.monitor = m
+
+	go m.run()
+	<-finished
+	assert.False(t, conn.Healthy())
+	conn.StopMonitor()
+
+	//1, 2, 4, 4, 4 milliseconds between the checks
+	for i, minimum := range []time.Duration{1, 2, 4, 4, 4} {
+		assert.GreaterOrEqual(t, waits[i+1], minimum*time.Millisecond)
+	}
+}
+
+func TestMonitorDefaults(t *testing.T) {
+	m := newMonitor(new(Connection), nil)
+	assert.Equal(t, DefaultMonitorInterval, m.config.Interval)
+	assert.Equal(t, DefaultPingTimeout, m.config.PingTimeout)
+	assert.Equal(t, DefaultMinBackoff, m.config.MinBackoff)
+	assert.Equal(t, DefaultMaxBackoff, m.config.MaxBackoff)
+}
+
+func TestMonitorNilConnection(t *testing.T) {
+	var conn *Connection
+	assert.NotPanics(t, func() {
+		conn.StopMonitor()
+		assert.Nil(t, Close(conn))
+	})
+}
@@ -0,0 +1,51 @@
+package gomongo
+
+import (
+	"fmt"
+
+	"github.com/globalsign/mgo/bson"
+)
+
+// StringID marks a plain string _id
+// A bare string passed as an id is always read as an ObjectId in its Hexadecimal form,
+// wrap it in StringID to match a record whose _id is the string itself
+type StringID string
+
+// UUID : Function returns the bson value of a UUID _id (binary subtype 4)
+func UUID(uuid [16]byte) bson.Binary {
+	return bson.Binary{Kind: 0x04, Data: uuid[:]}
+}
+
+// ParseObjectID : Function parses a Hexadecimal ObjectId, i.e. one coming from a URL parameter
+// Input Parameters
+//		hex(string) : the 24 characters Hexadecimal form of the ObjectId
+// Output Parameters
+//		bson.ObjectId : the parsed ObjectId
+//		error : ErrorInvalidID if hex isn't a valid ObjectId
+func ParseObjectID(hex string) (bson.ObjectId, error) {
+	if !bson.IsObjectIdHex(hex) {
+		return "", fmt.Errorf("%w: %q is not a Hexadecimal ObjectId", ErrorInvalidID, hex)
+	}
+	return bson.ObjectIdHex(hex), nil
+}
+
+// normalizeID : Function converts an id given by the caller into the _id value stored in the database
+// Hexadecimal strings become ObjectIds, StringID becomes a plain string and
+// every other value (ints, UUIDs, ObjectIds, etc) is used as it is
+func normalizeID(id interface{}) (interface{}, error) {
+	switch value := id.(type) {
+	case nil:
+		return nil, fmt.Errorf("%w: id is missing", ErrorInvalidID)
+	case string:
+		return ParseObjectID(value)
+	case StringID:
+		return string(value), nil
+	case bson.ObjectId:
+		if !value.Valid() {
+			return nil, fmt.Errorf("%w: %q is not a valid ObjectId", ErrorInvalidID, string(value))
+		}
+		return value, nil
+	default:
+		return id, nil
+	}
+}
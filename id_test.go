@@ -0,0 +1,52 @@
+package gomongo
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/globalsign/mgo/bson"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseObjectID(t *testing.T) {
+	id, err := ParseObjectID("5b28da94a34bd180f5ab0f5a")
+	assert.Nil(t, err)
+	assert.Equal(t, bson.ObjectIdHex("5b28da94a34bd180f5ab0f5a"), id)
+
+	for _, hex := range []string{"", "5b28da94", "not-an-object-id-at-all!", "5b28da94a34bd180f5ab0f5z"} {
+		_, err = ParseObjectID(hex)
+		assert.True(t, errors.Is(err, ErrorInvalidID), hex)
+	}
+}
+
+func TestNormalizeID(t *testing.T) {
+	uuid := [16]byte{0x12, 0x3e, 0x45, 0x67}
+	cases := []struct {
+		id       interface{}
+		expected interface{}
+	}{
+		{"5b28da94a34bd180f5ab0f5a", bson.ObjectIdHex("5b28da94a34bd180f5ab0f5a")},
+		{bson.ObjectIdHex("5b28da94a34bd180f5ab0f5a"), bson.ObjectIdHex("5b28da94a34bd180f5ab0f5a")},
+		{StringID("sku-42"), "sku-42"},
+		{42, 42},
+		{int64(42), int64(42)},
+		{UUID(uuid), bson.Binary{Kind: 0x04, Data: uuid[:]}},
+	}
+	for _, c := range cases {
+		id, err := normalizeID(c.id)
+		assert.Nil(t, err)
+		assert.Equal(t, c.expected, id)
+	}
+
+	for _, id := range []interface{}{nil, "sku-42", bson.ObjectId("short")} {
+		_, err := normalizeID(id)
+		assert.True(t, errors.Is(err, ErrorInvalidID), "%v", id)
+	}
+}
+
+func TestInvalidIDIsValidationError(t *testing.T) {
+	_, err := normalizeID("5b28da94")
+	err = newError("FindByID", "users", err)
+	assert.True(t, errors.Is(err, ErrorInvalidID))
+	assert.True(t, errors.Is(err, ErrorValidation))
+}
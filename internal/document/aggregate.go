@@ -0,0 +1,509 @@
+package document
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/globalsign/mgo/bson"
+)
+
+// Lookup returns every document of a collection, it feeds the $lookup stages of Aggregate
+type Lookup func(collection string) ([]bson.M, error)
+
+// Aggregate : Function runs pipeline over docs the way the aggregate command of MongoDB does
+// Supported stages : $match, $group, $sort, $project, $lookup, $unwind, $facet, $skip, $limit and $count
+// The expressions are field paths ("$age") and literals, $group knows $sum, $avg, $min, $max, $first, $last, $push and $addToSet
+// Input Parameters
+//		docs ([]bson.M) : documents of the collection, left untouched
+//		pipeline ([]bson.M) : stages, one operator each
+//		lookup (Lookup) : reads the collections joined by $lookup
+// Output Parameters
+//		[]bson.M : the documents out of the last stage
+//		error : ErrUnsupported for a stage or an expression not listed above, else an error if a stage is malformed
+func Aggregate(docs []bson.M, pipeline []bson.M, lookup Lookup) ([]bson.M, error) {
+	out := make([]bson.M, len(docs))
+	for i, doc := range docs {
+		out[i] = Clone(doc)
+	}
+	for _, stage := range pipeline {
+		if len(stage) != 1 {
+			return nil, fmt.Errorf("a pipeline stage needs exactly one operator, not %d", len(stage))
+		}
+		var err error
+		for op, spec := range stage {
+			out, err = runStage(out, op, spec, lookup)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
+
+// runStage : Function applies one stage to docs
+func runStage(docs []bson.M, op string, spec interface{}, lookup Lookup) ([]bson.M, error) {
+	switch op {
+	case "$match":
+		query, ok := asDocument(spec)
+		if !ok {
+			return nil, fmt.Errorf("$match needs a document")
+		}
+		var out []bson.M
+		for _, doc := range docs {
+			matched, err := Match(doc, query)
+			if err != nil {
+				return nil, err
+			}
+			if matched {
+				out = append(out, doc)
+			}
+		}
+		return out, nil
+	case "$sort":
+		keys, err := ParseSort(spec)
+		if err != nil {
+			return nil, err
+		}
+		Sort(docs, keys)
+		return docs, nil
+	case "$skip", "$limit":
+		n, ok := Number(spec)
+		if !ok || n < 0 {
+			return nil, fmt.Errorf("%s needs a positive number", op)
+		}
+		if op == "$skip" {
+			if int(n) >= len(docs) {
+				return nil, nil
+			}
+			return docs[int(n):], nil
+		}
+		if int(n) < len(docs) {
+			docs = docs[:int(n)]
+		}
+		return docs, nil
+	case "$count":
+		name, ok := spec.(string)
+		if !ok || len(name) == 0 || strings.HasPrefix(name, "$") {
+			return nil, fmt.Errorf("$count needs a field name")
+		}
+		if len(docs) == 0 {
+			return nil, nil
+		}
+		return []bson.M{{name: len(docs)}}, nil
+	case "$project":
+		fields, ok := asDocument(spec)
+		if !ok {
+			return nil, fmt.Errorf("$project needs a document")
+		}
+		return project(docs, fields)
+	case "$unwind":
+		return unwind(docs, spec)
+	case "$group":
+		fields, ok := asDocument(spec)
+		if !ok {
+			return nil, fmt.Errorf("$group needs a document")
+		}
+		return group(docs, fields)
+	case "$lookup":
+		return join(docs, spec, lookup)
+	case "$facet":
+		facets, ok := asDocument(spec)
+		if !ok {
+			return nil, fmt.Errorf("$facet needs a document")
+		}
+		result := bson.M{}
+		for name, value := range facets {
+			pipeline, err := asPipeline(value)
+			if err != nil {
+				return nil, err
+			}
+			out, err := Aggregate(docs, pipeline, lookup)
+			if err != nil {
+				return nil, err
+			}
+			list := make([]interface{}, len(out))
+			for i, doc := range out {
+				list[i] = doc
+			}
+			result[name] = list
+		}
+		return []bson.M{result}, nil
+	}
+	return nil, fmt.Errorf("%w %s", ErrUnsupported, op)
+}
+
+// ParseSort : Function reads the keys of a sort document, i.e. a $sort stage, given as bson.D to keep their order
+// or bson.M for a single key
+func ParseSort(spec interface{}) ([]SortKey, error) {
+	var fields bson.D
+	switch spec := spec.(type) {
+	case bson.D:
+		fields = spec
+	default:
+		doc, ok := asDocument(spec)
+		if !ok || len(doc) > 1 {
+			return nil, fmt.Errorf("$sort needs a bson.D, or a document with a single key")
+		}
+		for name, value := range doc {
+			fields = append(fields, bson.DocElem{Name: name, Value: value})
+		}
+	}
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("$sort needs at least one key")
+	}
+	keys := make([]SortKey, len(fields))
+	for i, field := range fields {
+		n, ok := Number(field.Value)
+		if !ok || n != 1 && n != -1 {
+			return nil, fmt.Errorf("the order of %s in $sort must be 1 or -1", field.Name)
+		}
+		keys[i] = SortKey{Path: field.Name, Desc: n < 0}
+	}
+	return keys, nil
+}
+
+// project : Function keeps, removes or computes the fields of docs, a field set to a path or a literal is computed
+func project(docs []bson.M, fields bson.M) ([]bson.M, error) {
+	selected := bson.M{}
+	computed := bson.M{}
+	for key, value := range fields {
+		switch value.(type) {
+		case bool, int, int32, int64, float64:
+			selected[key] = value
+		default:
+			computed[key] = value
+		}
+	}
+	if len(computed) > 0 {
+		//computed fields make an inclusion projection, the fields which aren't selected are dropped
+		if _, ok := selected["_id"]; !ok {
+			selected["_id"] = 1
+		}
+		for key, value := range selected {
+			if key != "_id" && !truthy(value) {
+				return nil, fmt.Errorf("$project can't exclude %s and compute fields", key)
+			}
+		}
+	}
+
+	out := make([]bson.M, len(docs))
+	for i, doc := range docs {
+		result := Project(doc, selected)
+		if len(computed) > 0 && len(selected) == 1 {
+			//only _id is selected, Project would keep the whole document
+			result = bson.M{}
+			if truthy(selected["_id"]) {
+				if id, ok := doc["_id"]; ok {
+					result["_id"] = id
+				}
+			}
+		}
+		for key, expr := range computed {
+			value, ok, err := evaluate(doc, expr)
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				if err := Set(result, key, value); err != nil {
+					return nil, err
+				}
+			}
+		}
+		out[i] = result
+	}
+	return out, nil
+}
+
+// unwind : Function outputs a document per element of the array at the path of spec
+func unwind(docs []bson.M, spec interface{}) ([]bson.M, error) {
+	var path string
+	preserve := false
+	switch spec := spec.(type) {
+	case string:
+		path = spec
+	default:
+		options, ok := asDocument(spec)
+		if !ok {
+			return nil, fmt.Errorf("$unwind needs a path or a document")
+		}
+		path, _ = options["path"].(string)
+		preserve = truthy(options["preserveNullAndEmptyArrays"])
+	}
+	if !strings.HasPrefix(path, "$") || len(path) == 1 {
+		return nil, fmt.Errorf("the path of $unwind must start with $")
+	}
+	path = path[1:]
+
+	var out []bson.M
+	for _, doc := range docs {
+		value, ok := valueAt(doc, path)
+		list, isList := asList(value)
+		switch {
+		case ok && value != nil && !isList:
+			out = append(out, doc)
+		case isList && len(list) > 0:
+			for _, elem := range list {
+				unwound := Clone(doc)
+				if err := Set(unwound, path, cloneValue(elem)); err != nil {
+					return nil, err
+				}
+				out = append(out, unwound)
+			}
+		case preserve:
+			out = append(out, doc)
+		}
+	}
+	return out, nil
+}
+
+// group : Function groups docs on the _id expression of fields and computes the accumulators of the other fields
+func group(docs []bson.M, fields bson.M) ([]bson.M, error) {
+	idExpr, ok := fields["_id"]
+	if !ok {
+		return nil, fmt.Errorf("$group needs an _id")
+	}
+	type accumulator struct {
+		field, op string
+		expr      interface{}
+	}
+	var accumulators []accumulator
+	for field, spec := range fields {
+		if field == "_id" {
+			continue
+		}
+		operators, ok := asDocument(spec)
+		if !ok || len(operators) != 1 {
+			return nil, fmt.Errorf("the field %s of $group needs a single accumulator", field)
+		}
+		for op, expr := range operators {
+			switch op {
+			case "$sum", "$avg", "$min", "$max", "$first", "$last", "$push", "$addToSet":
+			default:
+				return nil, fmt.Errorf("%w %s", ErrUnsupported, op)
+			}
+			accumulators = append(accumulators, accumulator{field: field, op: op, expr: expr})
+		}
+	}
+
+	type bucket struct {
+		id     interface{}
+		values [][]interface{} //values of every accumulator, in the order of the documents
+	}
+	var buckets []*bucket
+	for _, doc := range docs {
+		id, _, err := evaluate(doc, idExpr)
+		if err != nil {
+			return nil, err
+		}
+		var current *bucket
+		for _, b := range buckets {
+			if Equal(b.id, id) {
+				current = b
+				break
+			}
+		}
+		if current == nil {
+			current = &bucket{id: id, values: make([][]interface{}, len(accumulators))}
+			buckets = append(buckets, current)
+		}
+		for i, acc := range accumulators {
+			value, ok, err := evaluate(doc, acc.expr)
+			if err != nil {
+				return nil, err
+			}
+			if !ok {
+				value = nil
+			}
+			current.values[i] = append(current.values[i], value)
+		}
+	}
+
+	out := make([]bson.M, len(buckets))
+	for i, b := range buckets {
+		result := bson.M{"_id": b.id}
+		for j, acc := range accumulators {
+			result[acc.field] = accumulate(acc.op, b.values[j])
+		}
+		out[i] = result
+	}
+	return out, nil
+}
+
+// accumulate : Function folds the values of a group with a $group accumulator
+func accumulate(op string, values []interface{}) interface{} {
+	switch op {
+	case "$sum", "$avg":
+		var sum float64
+		count := 0
+		integer := true
+		for _, value := range values {
+			n, ok := Number(value)
+			if !ok {
+				continue
+			}
+			if _, isFloat := value.(float64); isFloat {
+				integer = false
+			}
+			sum += n
+			count++
+		}
+		if op == "$avg" {
+			if count == 0 {
+				return nil
+			}
+			return sum / float64(count)
+		}
+		if integer {
+			return int(sum)
+		}
+		return sum
+	case "$min", "$max":
+		var best interface{}
+		for _, value := range values {
+			if value == nil {
+				continue
+			}
+			if best == nil {
+				best = value
+				continue
+			}
+			c := compareSorted(value, best)
+			if op == "$min" && c < 0 || op == "$max" && c > 0 {
+				best = value
+			}
+		}
+		return best
+	case "$first":
+		if len(values) == 0 {
+			return nil
+		}
+		return values[0]
+	case "$last":
+		if len(values) == 0 {
+			return nil
+		}
+		return values[len(values)-1]
+	case "$push":
+		list := []interface{}{}
+		for _, value := range values {
+			if value != nil {
+				list = append(list, value)
+			}
+		}
+		return list
+	default: //$addToSet
+		list := []interface{}{}
+		for _, value := range values {
+			if value == nil {
+				continue
+			}
+			seen := false
+			for _, other := range list {
+				if Equal(other, value) {
+					seen = true
+					break
+				}
+			}
+			if !seen {
+				list = append(list, value)
+			}
+		}
+		return list
+	}
+}
+
+// join : Function adds to every document the list of the documents of another collection matching it, like $lookup
+func join(docs []bson.M, spec interface{}, lookup Lookup) ([]bson.M, error) {
+	options, ok := asDocument(spec)
+	if !ok {
+		return nil, fmt.Errorf("$lookup needs a document")
+	}
+	from, _ := options["from"].(string)
+	localField, _ := options["localField"].(string)
+	foreignField, _ := options["foreignField"].(string)
+	as, _ := options["as"].(string)
+	if len(from) == 0 || len(localField) == 0 || len(foreignField) == 0 || len(as) == 0 {
+		if _, ok := options["pipeline"]; ok {
+			return nil, fmt.Errorf("%w $lookup with a pipeline", ErrUnsupported)
+		}
+		return nil, fmt.Errorf("$lookup needs from, localField, foreignField and as")
+	}
+	if lookup == nil {
+		return nil, fmt.Errorf("%w $lookup", ErrUnsupported)
+	}
+	foreign, err := lookup(from)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, doc := range docs {
+		local, _ := Get(doc, localField)
+		query := bson.M{foreignField: local}
+		if list, ok := asList(local); ok {
+			query = bson.M{foreignField: bson.M{"$in": list}}
+		}
+		matches := []interface{}{}
+		for _, other := range foreign {
+			matched, err := Match(other, query)
+			if err != nil {
+				return nil, err
+			}
+			if matched {
+				matches = append(matches, Clone(other))
+			}
+		}
+		if err := Set(doc, as, matches); err != nil {
+			return nil, err
+		}
+	}
+	return docs, nil
+}
+
+// evaluate : Function evaluates an expression against doc: "$path" reads a field, a document evaluates
+// each of its fields and anything else is a literal
+// Output Parameters
+//		interface{} : the value
+//		bool : false when a path is missing from doc
+//		error : ErrUnsupported for an expression operator
+func evaluate(doc bson.M, expr interface{}) (interface{}, bool, error) {
+	if path, ok := expr.(string); ok && strings.HasPrefix(path, "$") {
+		value, found := Get(doc, path[1:])
+		return value, found, nil
+	}
+	sub, ok := asDocument(expr)
+	if !ok {
+		return expr, true, nil
+	}
+	result := bson.M{}
+	for key, value := range sub {
+		if strings.HasPrefix(key, "$") {
+			return nil, false, fmt.Errorf("%w %s", ErrUnsupported, key)
+		}
+		evaluated, found, err := evaluate(doc, value)
+		if err != nil {
+			return nil, false, err
+		}
+		if found {
+			result[key] = evaluated
+		}
+	}
+	return result, true, nil
+}
+
+// asPipeline : Function reads the stages of a $facet
+func asPipeline(value interface{}) ([]bson.M, error) {
+	if pipeline, ok := value.([]bson.M); ok {
+		return pipeline, nil
+	}
+	list, ok := asList(value)
+	if !ok {
+		return nil, fmt.Errorf("$facet needs a pipeline per field")
+	}
+	pipeline := make([]bson.M, len(list))
+	for i, stage := range list {
+		if pipeline[i], ok = asDocument(stage); !ok {
+			return nil, fmt.Errorf("$facet needs a pipeline per field")
+		}
+	}
+	return pipeline, nil
+}
@@ -0,0 +1,473 @@
+// Package document evaluates the MongoDB query and update operators for the drivers
+// which store the documents themselves instead of sending them to a MongoDB server
+package document
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/globalsign/mgo/bson"
+)
+
+var (
+	// ErrUnsupported is returned for an operator the drivers don't implement
+	ErrUnsupported = errors.New("unsupported operator")
+	// ErrImmutableID is returned by an update which changes the _id of a document
+	ErrImmutableID = errors.New("the _id of a document can't be changed")
+)
+
+// Get : Function returns the value at the dotted path of doc i.e, "address.city"
+// Like MongoDB, a path crossing an array goes on into each of its documents: "items.name" returns the names
+// of the documents of items as one array, the arrays found being flattened, and a numeric key i.e, "items.0"
+// selects an element. The value is false when no document of the array has the rest of the path
+func Get(doc bson.M, path string) (interface{}, bool) {
+	return get(doc, strings.Split(path, "."))
+}
+
+// get : Function returns the value at keys of value, going into the arrays met on the way
+func get(value interface{}, keys []string) (interface{}, bool) {
+	if len(keys) == 0 {
+		return value, true
+	}
+	if sub, ok := asDocument(value); ok {
+		next, ok := sub[keys[0]]
+		if !ok {
+			return nil, false
+		}
+		return get(next, keys[1:])
+	}
+	list, ok := asList(value)
+	if !ok {
+		return nil, false
+	}
+	if i, err := strconv.Atoi(keys[0]); err == nil && i >= 0 {
+		if i >= len(list) {
+			return nil, false
+		}
+		return get(list[i], keys[1:])
+	}
+	var found []interface{}
+	for _, item := range list {
+		if _, ok := asDocument(item); !ok {
+			continue
+		}
+		value, ok := get(item, keys)
+		if !ok {
+			continue
+		}
+		if items, ok := asList(value); ok {
+			found = append(found, items...)
+		} else {
+			found = append(found, value)
+		}
+	}
+	if found == nil {
+		return nil, false
+	}
+	return found, true
+}
+
+// valueAt : Function returns the value at the dotted path of doc without going into arrays,
+// for the updates, the projections and $unwind which set the value back at the same path
+func valueAt(doc bson.M, path string) (interface{}, bool) {
+	var value interface{} = doc
+	for _, key := range strings.Split(path, ".") {
+		sub, ok := asDocument(value)
+		if !ok {
+			return nil, false
+		}
+		if value, ok = sub[key]; !ok {
+			return nil, false
+		}
+	}
+	return value, true
+}
+
+// Set : Function sets the value at the dotted path of doc, creating the missing embedded documents
+func Set(doc bson.M, path string, value interface{}) error {
+	keys := strings.Split(path, ".")
+	for _, key := range keys[:len(keys)-1] {
+		next, ok := doc[key]
+		if !ok || next == nil {
+			sub := bson.M{}
+			doc[key] = sub
+			doc = sub
+			continue
+		}
+		sub, ok := asDocument(next)
+		if !ok {
+			return fmt.Errorf("can't set %s, %s is not a document", path, key)
+		}
+		doc = sub
+	}
+	doc[keys[len(keys)-1]] = value
+	return nil
+}
+
+// Unset : Function removes the value at the dotted path of doc
+func Unset(doc bson.M, path string) {
+	keys := strings.Split(path, ".")
+	for _, key := range keys[:len(keys)-1] {
+		sub, ok := asDocument(doc[key])
+		if !ok {
+			return
+		}
+		doc = sub
+	}
+	delete(doc, keys[len(keys)-1])
+}
+
+// Clone : Function returns a deep copy of doc, so it can be modified without touching the original
+func Clone(doc bson.M) bson.M {
+	if doc == nil {
+		return nil
+	}
+	return cloneValue(doc).(bson.M)
+}
+
+func cloneValue(value interface{}) interface{} {
+	if sub, ok := asDocument(value); ok {
+		clone := make(bson.M, len(sub))
+		for key, item := range sub {
+			clone[key] = cloneValue(item)
+		}
+		return clone
+	}
+	if list, ok := value.([]interface{}); ok {
+		clone := make([]interface{}, len(list))
+		for i, item := range list {
+			clone[i] = cloneValue(item)
+		}
+		return clone
+	}
+	return value
+}
+
+// Match : Function reports if doc matches query
+// Supported : implicit equality, $eq, $ne, $gt, $gte, $lt, $lte, $in, $nin, $exists, $not, $and, $or and $nor
+// A condition on an array field matches when one of its elements matches, like MongoDB does,
+// and a dotted path crossing arrays is read with Get
+func Match(doc bson.M, query bson.M) (bool, error) {
+	for key, condition := range query {
+		var ok bool
+		var err error
+		switch key {
+		case "$and", "$or", "$nor":
+			ok, err = matchLogical(doc, key, condition)
+		default:
+			if strings.HasPrefix(key, "$") {
+				return false, fmt.Errorf("%w %s", ErrUnsupported, key)
+			}
+			value, exists := Get(doc, key)
+			ok, err = matchField(value, exists, condition)
+		}
+		if err != nil || !ok {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// matchLogical : Function evaluates $and, $or and $nor
+func matchLogical(doc bson.M, op string, condition interface{}) (bool, error) {
+	clauses, ok := asList(condition)
+	if !ok || len(clauses) == 0 {
+		return false, fmt.Errorf("%s needs a non empty array", op)
+	}
+	for _, clause := range clauses {
+		query, ok := asDocument(clause)
+		if !ok {
+			return false, fmt.Errorf("%s needs an array of documents", op)
+		}
+		matched, err := Match(doc, query)
+		if err != nil {
+			return false, err
+		}
+		switch {
+		case op == "$and" && !matched:
+			return false, nil
+		case op == "$or" && matched:
+			return true, nil
+		case op == "$nor" && matched:
+			return false, nil
+		}
+	}
+	return op != "$or", nil
+}
+
+// matchField : Function evaluates the condition on a single field
+func matchField(value interface{}, exists bool, condition interface{}) (bool, error) {
+	operators, ok := Operators(condition)
+	if !ok {
+		return equals(value, exists, condition), nil
+	}
+	for op, operand := range operators {
+		var ok bool
+		switch op {
+		case "$eq":
+			ok = equals(value, exists, operand)
+		case "$ne":
+			ok = !equals(value, exists, operand)
+		case "$gt", "$gte", "$lt", "$lte":
+			ok = exists && anyElement(value, func(item interface{}) bool {
+				c, comparable := Compare(item, operand)
+				return comparable && compares(op, c)
+			})
+		case "$in", "$nin":
+			list, isList := asList(operand)
+			if !isList {
+				return false, fmt.Errorf("%s needs an array", op)
+			}
+			for _, candidate := range list {
+				if ok = equals(value, exists, candidate); ok {
+					break
+				}
+			}
+			if op == "$nin" {
+				ok = !ok
+			}
+		case "$exists":
+			ok = exists == truthy(operand)
+		case "$not":
+			matched, err := matchField(value, exists, operand)
+			if err != nil {
+				return false, err
+			}
+			ok = !matched
+		default:
+			return false, fmt.Errorf("%w %s", ErrUnsupported, op)
+		}
+		if !ok {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// compares : Function tells if the result c of Compare satisfies op
+func compares(op string, c int) bool {
+	switch op {
+	case "$gt":
+		return c > 0
+	case "$gte":
+		return c >= 0
+	case "$lt":
+		return c < 0
+	default:
+		return c <= 0
+	}
+}
+
+// equals : Function tells if the field value equals want, a missing field equals nil
+func equals(value interface{}, exists bool, want interface{}) bool {
+	if want == nil {
+		return !exists || value == nil
+	}
+	if !exists {
+		return false
+	}
+	if Equal(value, want) {
+		return true
+	}
+	if list, ok := value.([]interface{}); ok {
+		for _, item := range list {
+			if Equal(item, want) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// anyElement : Function applies test to value, or to its elements when it's an array
+func anyElement(value interface{}, test func(interface{}) bool) bool {
+	if list, ok := value.([]interface{}); ok {
+		for _, item := range list {
+			if test(item) {
+				return true
+			}
+		}
+		return false
+	}
+	return test(value)
+}
+
+// Distinct : Function returns the distinct values at the dotted path of docs, in the order they're met
+// The elements of an array are taken one by one like MongoDB does, the documents missing the path are skipped
+func Distinct(docs []bson.M, path string) []interface{} {
+	values := []interface{}{}
+	add := func(value interface{}) {
+		for _, seen := range values {
+			if Equal(seen, value) {
+				return
+			}
+		}
+		values = append(values, value)
+	}
+	for _, doc := range docs {
+		value, ok := Get(doc, path)
+		if !ok {
+			continue
+		}
+		if list, isList := asList(value); isList {
+			for _, elem := range list {
+				add(elem)
+			}
+			continue
+		}
+		add(value)
+	}
+	return values
+}
+
+// Equal : Function compares two values the way MongoDB does, numbers being equal whatever their type
+func Equal(a, b interface{}) bool {
+	if c, ok := Compare(a, b); ok {
+		return c == 0
+	}
+	if subA, ok := asDocument(a); ok {
+		subB, ok := asDocument(b)
+		if !ok || len(subA) != len(subB) {
+			return false
+		}
+		for key, value := range subA {
+			other, ok := subB[key]
+			if !ok || !Equal(value, other) {
+				return false
+			}
+		}
+		return true
+	}
+	if listA, ok := asList(a); ok {
+		listB, ok := asList(b)
+		if !ok || len(listA) != len(listB) {
+			return false
+		}
+		for i := range listA {
+			if !Equal(listA[i], listB[i]) {
+				return false
+			}
+		}
+		return true
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+// Compare : Function orders two scalar values of the same kind (numbers, strings, dates, ObjectIds, booleans)
+// Output Parameters
+//		int : -1, 0 or 1
+//		bool : false if the values can't be compared
+func Compare(a, b interface{}) (int, bool) {
+	if x, ok := Number(a); ok {
+		y, ok := Number(b)
+		if !ok {
+			return 0, false
+		}
+		return compareOrdered(x, y), true
+	}
+	switch x := a.(type) {
+	case string:
+		if y, ok := b.(string); ok {
+			return strings.Compare(x, y), true
+		}
+	case bson.ObjectId:
+		if y, ok := b.(bson.ObjectId); ok {
+			return strings.Compare(string(x), string(y)), true
+		}
+	case time.Time:
+		if y, ok := b.(time.Time); ok {
+			return x.Compare(y), true
+		}
+	case bool:
+		if y, ok := b.(bool); ok {
+			switch {
+			case x == y:
+				return 0, true
+			case y:
+				return -1, true
+			default:
+				return 1, true
+			}
+		}
+	}
+	return 0, false
+}
+
+func compareOrdered(x, y float64) int {
+	switch {
+	case x < y:
+		return -1
+	case x > y:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// Number : Function returns the value of any Go number as a float64
+func Number(value interface{}) (float64, bool) {
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(v.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), true
+	}
+	return 0, false
+}
+
+// Operators : Function returns condition as a map of operators when all its keys start with "$"
+func Operators(condition interface{}) (bson.M, bool) {
+	operators, ok := asDocument(condition)
+	if !ok || len(operators) == 0 {
+		return nil, false
+	}
+	for key := range operators {
+		if !strings.HasPrefix(key, "$") {
+			return nil, false
+		}
+	}
+	return operators, true
+}
+
+// asDocument : Function returns value as a bson.M when it's an embedded document
+func asDocument(value interface{}) (bson.M, bool) {
+	switch sub := value.(type) {
+	case bson.M:
+		return sub, true
+	case map[string]interface{}:
+		return bson.M(sub), true
+	}
+	return nil, false
+}
+
+// asList : Function returns value as a []interface{} when it's an array of any type
+func asList(value interface{}) ([]interface{}, bool) {
+	if list, ok := value.([]interface{}); ok {
+		return list, true
+	}
+	v := reflect.ValueOf(value)
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array || v.Type().Elem().Kind() == reflect.Uint8 {
+		return nil, false
+	}
+	list := make([]interface{}, v.Len())
+	for i := range list {
+		list[i] = v.Index(i).Interface()
+	}
+	return list, true
+}
+
+// truthy : Function reads the boolean operands of $exists and the projections, given as bool or number
+func truthy(value interface{}) bool {
+	if b, ok := value.(bool); ok {
+		return b
+	}
+	n, ok := Number(value)
+	return ok && n != 0
+}
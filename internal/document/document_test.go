@@ -0,0 +1,228 @@
+package document
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/globalsign/mgo/bson"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatch(t *testing.T) {
+	now := time.Now()
+	doc := bson.M{
+		"_id":     bson.NewObjectId(),
+		"name":    "Amulya",
+		"age":     26.0,
+		"tags":    []interface{}{"admin", "ops"},
+		"address": bson.M{"city": "Pune"},
+		"joined":  now,
+		"items":   []interface{}{bson.M{"name": "pen", "price": 3, "colors": []interface{}{"red"}}, bson.M{"name": "ink"}, "loose"},
+	}
+	matching := []bson.M{
+		{},
+		{"name": "Amulya"},
+		{"age": 26},
+		{"age": bson.M{"$gt": 25, "$lte": 26}},
+		{"tags": "ops"},
+		{"address.city": bson.M{"$in": []string{"Pune", "Delhi"}}},
+		{"missing": nil},
+		{"missing": bson.M{"$exists": false}},
+		{"name": bson.M{"$ne": "Ravi"}},
+		{"age": bson.M{"$not": bson.M{"$gt": 30}}},
+		{"joined": bson.M{"$lt": now.Add(time.Second)}},
+		{"$or": []interface{}{bson.M{"name": "Ravi"}, bson.M{"age": 26}}},
+		{"$and": []interface{}{bson.M{"name": "Amulya"}, bson.M{"tags": bson.M{"$nin": []interface{}{"guest"}}}}},
+		{"items.name": "ink"},
+		{"items.price": bson.M{"$gt": 2}},
+		{"items.colors": "red"},
+		{"items.1.name": "ink"},
+		{"items.name": bson.M{"$exists": true}},
+	}
+	for _, query := range matching {
+		ok, err := Match(doc, query)
+		assert.Nil(t, err)
+		assert.True(t, ok, "%v", query)
+	}
+
+	failing := []bson.M{
+		{"name": "Ravi"},
+		{"age": bson.M{"$gt": "26"}},
+		{"address": bson.M{"city": "Delhi"}},
+		{"name": bson.M{"$exists": false}},
+		{"$nor": []interface{}{bson.M{"name": "Amulya"}}},
+		{"items.name": "pencil"},
+		{"items.name": bson.M{"$ne": "pen"}},
+		{"items.0.name": "ink"},
+		{"items.weight": bson.M{"$exists": true}},
+	}
+	for _, query := range failing {
+		ok, err := Match(doc, query)
+		assert.Nil(t, err)
+		assert.False(t, ok, "%v", query)
+	}
+
+	_, err := Match(doc, bson.M{"name": bson.M{"$regex": "^A"}})
+	assert.True(t, errors.Is(err, ErrUnsupported))
+}
+
+func TestApply(t *testing.T) {
+	doc := bson.M{"_id": 1, "name": "Amulya", "age": 26, "tags": []interface{}{"admin"}}
+
+	updated, err := Apply(doc, bson.M{
+		"$set":         bson.M{"address.city": "Pune"},
+		"$inc":         bson.M{"age": 1, "visits": 1},
+		"$unset":       bson.M{"name": ""},
+		"$push":        bson.M{"tags": bson.M{"$each": []interface{}{"ops", "dev"}}},
+		"$setOnInsert": bson.M{"created": true},
+	}, false)
+	assert.Nil(t, err)
+	assert.Equal(t, bson.M{"_id": 1, "age": 27, "visits": 1, "address": bson.M{"city": "Pune"}, "tags": []interface{}{"admin", "ops", "dev"}}, updated)
+	assert.Equal(t, "Amulya", doc["name"], "the original is left untouched")
+
+	replaced, err := Apply(doc, bson.M{"name": "Ravi"}, false)
+	assert.Nil(t, err)
+	assert.Equal(t, bson.M{"_id": 1, "name": "Ravi"}, replaced)
+
+	_, err = Apply(doc, bson.M{"$set": bson.M{"_id": 2}}, false)
+	assert.True(t, errors.Is(err, ErrImmutableID))
+
+	_, err = Apply(doc, bson.M{"$rename": bson.M{"name": "firstName"}}, false)
+	assert.True(t, errors.Is(err, ErrUnsupported))
+}
+
+func TestUpserted(t *testing.T) {
+	doc, err := Upserted(bson.M{"_id": "anu", "age": bson.M{"$gt": 20}, "city": bson.M{"$eq": "Pune"}}, bson.M{"$set": bson.M{"name": "Anu"}, "$setOnInsert": bson.M{"created": true}})
+	assert.Nil(t, err)
+	assert.Equal(t, bson.M{"_id": "anu", "city": "Pune", "name": "Anu", "created": true}, doc)
+}
+
+func TestProject(t *testing.T) {
+	doc := bson.M{"_id": 1, "name": "Amulya", "password": "secret", "address": bson.M{"city": "Pune", "zip": "411001"}}
+
+	assert.Equal(t, doc, Project(doc, nil))
+	assert.Equal(t, bson.M{"_id": 1, "name": "Amulya"}, Project(doc, bson.M{"name": 1}))
+	assert.Equal(t, bson.M{"address": bson.M{"city": "Pune"}}, Project(doc, bson.M{"_id": 0, "address.city": true}))
+	assert.Equal(t, bson.M{"_id": 1, "name": "Amulya", "address": bson.M{"city": "Pune", "zip": "411001"}}, Project(doc, bson.M{"password": 0}))
+}
+
+func TestSort(t *testing.T) {
+	docs := []bson.M{
+		{"_id": 1, "name": "Ravi", "age": 40},
+		{"_id": 2, "name": "Amulya", "age": 26.0},
+		{"_id": 3, "name": "Kasyap", "age": 40},
+		{"_id": 4, "name": "Anu"},
+		{"_id": 5, "name": "Zed", "age": "unknown"},
+	}
+	ids := func() []interface{} {
+		var ids []interface{}
+		for _, doc := range docs {
+			ids = append(ids, doc["_id"])
+		}
+		return ids
+	}
+
+	Sort(docs, []SortKey{{Path: "age"}})
+	assert.Equal(t, []interface{}{4, 2, 1, 3, 5}, ids(), "missing first, then numbers, then strings, ties keep their order")
+
+	Sort(docs, []SortKey{{Path: "age", Desc: true}, {Path: "name"}})
+	assert.Equal(t, []interface{}{5, 3, 1, 2, 4}, ids())
+
+	//First picks the earliest of the documents sorted first
+	assert.Equal(t, 4, First(docs, []SortKey{{Path: "age"}}))
+	assert.Equal(t, 3, First(docs, []SortKey{{Path: "name"}}))
+	assert.Equal(t, 0, First(docs[1:3], []SortKey{{Path: "age"}}), "Kasyap and Ravi are both 40")
+	assert.Equal(t, 0, First(docs, nil))
+	assert.Equal(t, -1, First(nil, nil))
+
+	//an array is sorted on its smallest element, its largest in descending order
+	docs = []bson.M{
+		{"_id": 1, "items": []interface{}{bson.M{"price": 5}, bson.M{"price": 1}}},
+		{"_id": 2, "items": []interface{}{bson.M{"price": 3}}},
+		{"_id": 3, "items": []interface{}{bson.M{"price": 2}, bson.M{"price": 9}}},
+	}
+	Sort(docs, []SortKey{{Path: "items.price"}})
+	assert.Equal(t, []interface{}{1, 3, 2}, ids())
+	Sort(docs, []SortKey{{Path: "items.price", Desc: true}})
+	assert.Equal(t, []interface{}{3, 1, 2}, ids())
+}
+
+func TestDistinct(t *testing.T) {
+	docs := []bson.M{
+		{"city": "Pune", "tags": []interface{}{"a", "b"}},
+		{"city": "Goa", "tags": []string{"b", "c"}},
+		{"city": "Pune"},
+		{"age": 3},
+		{"city": 1, "tags": "a"},
+		{"address": []interface{}{bson.M{"city": "Goa"}, bson.M{"city": "Agra"}}},
+	}
+	assert.Equal(t, []interface{}{"Pune", "Goa", 1}, Distinct(docs, "city"))
+	assert.Equal(t, []interface{}{"a", "b", "c"}, Distinct(docs, "tags"))
+	assert.Equal(t, []interface{}{}, Distinct(docs, "missing"))
+	assert.Equal(t, []interface{}{"Goa", "Agra"}, Distinct(docs, "address.city"))
+}
+
+func TestUnmarshalJSON(t *testing.T) {
+	doc := bson.M{"i": 3, "f": 2.5, "big": 1e21, "long": int64(1) << 60, "sub": bson.M{"n": -4}, "list": []interface{}{1, 0.5}}
+	data, err := bson.MarshalJSON(doc)
+	assert.Nil(t, err)
+	var decoded bson.M
+	assert.Nil(t, UnmarshalJSON(data, &decoded))
+	assert.Equal(t, 3, decoded["i"])
+	assert.Equal(t, 2.5, decoded["f"])
+	assert.Equal(t, 1e21, decoded["big"], "beyond 2^53 a number stays a float64")
+	assert.Equal(t, int64(1)<<60, decoded["long"])
+	sub, _ := asDocument(decoded["sub"])
+	assert.Equal(t, -4, sub["n"])
+	assert.Equal(t, []interface{}{1, 0.5}, decoded["list"])
+	assert.NotNil(t, UnmarshalJSON([]byte("[1]"), &decoded))
+}
+
+func TestAggregate(t *testing.T) {
+	orders := []bson.M{
+		{"_id": 1, "customer": "a", "total": 10, "items": []interface{}{"pen", "ink"}},
+		{"_id": 2, "customer": "b", "total": 5, "items": []interface{}{"pen"}},
+		{"_id": 3, "customer": "a", "total": 7.5, "items": []interface{}{}},
+	}
+	customers := []bson.M{{"_id": "a", "name": "Amulya"}, {"_id": "b", "name": "Kasyap"}}
+	lookup := func(collection string) ([]bson.M, error) {
+		assert.Equal(t, "customers", collection)
+		return customers, nil
+	}
+
+	out, err := Aggregate(orders, []bson.M{
+		{"$match": bson.M{"total": bson.M{"$gt": 6}}},
+		{"$group": bson.M{"_id": "$customer", "spent": bson.M{"$sum": "$total"}, "orders": bson.M{"$push": "$_id"}}},
+	}, lookup)
+	assert.Nil(t, err)
+	assert.Equal(t, []bson.M{{"_id": "a", "spent": 17.5, "orders": []interface{}{1, 3}}}, out)
+
+	out, err = Aggregate(orders, []bson.M{
+		{"$unwind": "$items"},
+		{"$sort": bson.D{{Name: "items", Value: 1}, {Name: "_id", Value: -1}}},
+		{"$project": bson.M{"_id": 0, "item": "$items", "order": "$_id"}},
+	}, lookup)
+	assert.Nil(t, err)
+	assert.Equal(t, []bson.M{{"item": "ink", "order": 1}, {"item": "pen", "order": 2}, {"item": "pen", "order": 1}}, out)
+
+	out, err = Aggregate(orders, []bson.M{
+		{"$lookup": bson.M{"from": "customers", "localField": "customer", "foreignField": "_id", "as": "buyer"}},
+		{"$unwind": bson.M{"path": "$buyer"}},
+		{"$facet": bson.M{
+			"count":   []bson.M{{"$count": "n"}},
+			"biggest": []bson.M{{"$sort": bson.M{"total": -1}}, {"$limit": 1}, {"$project": bson.M{"buyer.name": 1}}},
+		}},
+	}, lookup)
+	assert.Nil(t, err)
+	assert.Equal(t, []bson.M{{
+		"count":   []interface{}{bson.M{"n": 3}},
+		"biggest": []interface{}{bson.M{"_id": 1, "buyer": bson.M{"name": "Amulya"}}},
+	}}, out)
+	assert.Len(t, orders[0], 4, "the documents are left untouched")
+
+	_, err = Aggregate(orders, []bson.M{{"$bucket": bson.M{}}}, lookup)
+	assert.True(t, errors.Is(err, ErrUnsupported))
+	_, err = Aggregate(orders, []bson.M{{"$project": bson.M{"total": bson.M{"$multiply": []interface{}{"$total", 2}}}}}, lookup)
+	assert.True(t, errors.Is(err, ErrUnsupported))
+}
@@ -0,0 +1,51 @@
+package document
+
+import (
+	"math"
+
+	"github.com/globalsign/mgo/bson"
+)
+
+// maxExactInteger is the largest integer a float64 holds exactly, bson.MarshalJSON writes the larger ones as $numberLong
+const maxExactInteger = 1 << 53
+
+// UnmarshalJSON : Function decodes the extended JSON written by bson.MarshalJSON into doc, keeping the integers integers
+// JSON has a single number type which bson.UnmarshalJSON reads as float64, the integral numbers are turned back into int
+// (a float64 without a fractional part, i.e 3.0, reads back as the int 3 too) and $numberLong stays int64
+// Input Parameters
+//		data ([]byte) : extended JSON of a document
+//		doc (*bson.M) : document to decode into
+// Output Parameters
+//		error : the JSON isn't a document
+func UnmarshalJSON(data []byte, doc *bson.M) error {
+	if err := bson.UnmarshalJSON(data, doc); err != nil {
+		return err
+	}
+	for key, value := range *doc {
+		(*doc)[key] = integers(value)
+	}
+	return nil
+}
+
+// integers : Function returns value with its integral float64 turned into int, recursing into documents and arrays
+func integers(value interface{}) interface{} {
+	switch v := value.(type) {
+	case float64:
+		if v == math.Trunc(v) && math.Abs(v) <= maxExactInteger {
+			return int(v)
+		}
+	case bson.M:
+		for key, item := range v {
+			v[key] = integers(item)
+		}
+	case map[string]interface{}:
+		for key, item := range v {
+			v[key] = integers(item)
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = integers(item)
+		}
+	}
+	return value
+}
@@ -0,0 +1,108 @@
+package document
+
+import (
+	"sort"
+	"time"
+
+	"github.com/globalsign/mgo/bson"
+)
+
+// SortKey is a field the documents are sorted on
+type SortKey struct {
+	Path string //dotted path of the field
+	Desc bool   //descending order
+}
+
+// Sort : Function sorts docs in place on keys, keeping the order of the documents which compare equal
+// Values of different types are ordered like MongoDB does: missing and null first, then numbers, strings,
+// documents, empty arrays, ObjectIds, booleans and dates. An array is sorted on its smallest element,
+// its largest in descending order
+func Sort(docs []bson.M, keys []SortKey) {
+	sort.SliceStable(docs, func(i, j int) bool {
+		return less(docs[i], docs[j], keys)
+	})
+}
+
+// First : Function returns the index of the document sorted first on keys, the earliest of the ones which compare equal,
+// -1 when docs is empty
+func First(docs []bson.M, keys []SortKey) int {
+	first := -1
+	for i := range docs {
+		if first < 0 || less(docs[i], docs[first], keys) {
+			first = i
+		}
+	}
+	return first
+}
+
+// less : Function reports if a is sorted before b on keys
+func less(a, b bson.M, keys []SortKey) bool {
+	for _, key := range keys {
+		x, _ := Get(a, key.Path)
+		y, _ := Get(b, key.Path)
+		c := compareSorted(sortValue(x, key.Desc), sortValue(y, key.Desc))
+		if c == 0 {
+			continue
+		}
+		if key.Desc {
+			return c > 0
+		}
+		return c < 0
+	}
+	return false
+}
+
+// sortValue : Function returns the value an array is sorted on like MongoDB does, its smallest element
+// in ascending order and its largest in descending order, other values being sorted on themselves
+func sortValue(value interface{}, desc bool) interface{} {
+	list, ok := asList(value)
+	if !ok || len(list) == 0 {
+		return value
+	}
+	result := list[0]
+	for _, item := range list[1:] {
+		if c := compareSorted(item, result); c < 0 && !desc || c > 0 && desc {
+			result = item
+		}
+	}
+	return result
+}
+
+// compareSorted : Function orders two values of any type, -1, 0 or 1
+func compareSorted(a, b interface{}) int {
+	rankA, rankB := typeRank(a), typeRank(b)
+	if rankA != rankB {
+		return compareOrdered(float64(rankA), float64(rankB))
+	}
+	if c, ok := Compare(a, b); ok {
+		return c
+	}
+	return 0
+}
+
+// typeRank : Function returns the rank of the type of value in the MongoDB sort order
+func typeRank(value interface{}) int {
+	if value == nil {
+		return 0
+	}
+	if _, ok := Number(value); ok {
+		return 1
+	}
+	switch value.(type) {
+	case string:
+		return 2
+	case bson.ObjectId:
+		return 5
+	case bool:
+		return 6
+	case time.Time:
+		return 7
+	}
+	if _, ok := asDocument(value); ok {
+		return 3
+	}
+	if _, ok := asList(value); ok {
+		return 4
+	}
+	return 8
+}
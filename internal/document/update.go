@@ -0,0 +1,221 @@
+package document
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/globalsign/mgo/bson"
+)
+
+// IsReplacement : Function tells if update is a whole document rather than a set of update operators
+func IsReplacement(update bson.M) bool {
+	for key := range update {
+		if strings.HasPrefix(key, "$") {
+			return false
+		}
+	}
+	return true
+}
+
+// Apply : Function returns a copy of doc modified by update, doc itself is left untouched
+// update is either a replacement document, which keeps the _id of doc, or a set of operators among
+// $set, $unset, $inc, $push and $setOnInsert (only applied when inserting)
+// Input Parameters
+//		doc (bson.M) : the current document
+//		update (bson.M) : replacement document or update operators
+//		inserting (bool) : true when the document is being created by an upsert
+// Output Parameters
+//		bson.M : the updated document
+//		error : ErrUnsupported, ErrImmutableID or a type mismatch
+func Apply(doc, update bson.M, inserting bool) (bson.M, error) {
+	id, hasID := doc["_id"]
+
+	var result bson.M
+	if IsReplacement(update) {
+		result = Clone(update)
+		if hasID {
+			if replaced, ok := result["_id"]; ok && !Equal(replaced, id) {
+				return nil, ErrImmutableID
+			}
+			result["_id"] = id
+		}
+		return result, nil
+	}
+
+	result = Clone(doc)
+	for op, fields := range update {
+		changes, ok := asDocument(fields)
+		if !ok {
+			return nil, fmt.Errorf("%s needs a document", op)
+		}
+		for path, value := range changes {
+			if err := applyOperator(result, op, path, value, inserting); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if hasID {
+		if updated, ok := result["_id"]; !ok || !Equal(updated, id) {
+			return nil, ErrImmutableID
+		}
+	}
+	return result, nil
+}
+
+// applyOperator : Function applies a single update operator to the field at path
+func applyOperator(doc bson.M, op, path string, value interface{}, inserting bool) error {
+	switch op {
+	case "$set":
+		return Set(doc, path, cloneValue(value))
+	case "$setOnInsert":
+		if inserting {
+			return Set(doc, path, cloneValue(value))
+		}
+		return nil
+	case "$unset":
+		Unset(doc, path)
+		return nil
+	case "$inc":
+		current, ok := valueAt(doc, path)
+		if !ok || current == nil {
+			return Set(doc, path, value)
+		}
+		sum, err := add(current, value)
+		if err != nil {
+			return fmt.Errorf("can't $inc %s: %v", path, err)
+		}
+		return Set(doc, path, sum)
+	case "$push":
+		items := []interface{}{value}
+		if operators, ok := Operators(value); ok {
+			each, ok := asList(operators["$each"])
+			if !ok || len(operators) > 1 {
+				return fmt.Errorf("%w in $push, only $each is supported", ErrUnsupported)
+			}
+			items = each
+		}
+		current, ok := valueAt(doc, path)
+		if !ok || current == nil {
+			current = []interface{}{}
+		}
+		list, ok := current.([]interface{})
+		if !ok {
+			return fmt.Errorf("can't $push to %s, it's not an array", path)
+		}
+		pushed := append(append([]interface{}{}, list...), cloneValue(items).([]interface{})...)
+		return Set(doc, path, pushed)
+	default:
+		return fmt.Errorf("%w %s", ErrUnsupported, op)
+	}
+}
+
+// add : Function adds two numbers, integers stay integers of the type of a
+func add(a, b interface{}) (interface{}, error) {
+	x, ok := Number(a)
+	y, ok2 := Number(b)
+	if !ok || !ok2 {
+		return nil, fmt.Errorf("%v and %v aren't both numbers", a, b)
+	}
+	if isInteger(a) && isInteger(b) {
+		sum := reflect.ValueOf(a).Int() + reflect.ValueOf(b).Int()
+		return reflect.ValueOf(sum).Convert(reflect.TypeOf(a)).Interface(), nil
+	}
+	return x + y, nil
+}
+
+func isInteger(value interface{}) bool {
+	switch reflect.ValueOf(value).Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return true
+	}
+	return false
+}
+
+// Upserted : Function builds the document inserted by an upsert which matched nothing
+// The equality conditions of query are copied in the document before update is applied
+func Upserted(query, update bson.M) (bson.M, error) {
+	seed := bson.M{}
+	if err := seedEqualities(seed, query); err != nil {
+		return nil, err
+	}
+	if IsReplacement(update) {
+		doc := Clone(update)
+		if id, ok := seed["_id"]; ok {
+			if _, ok := doc["_id"]; !ok {
+				doc["_id"] = id
+			}
+		}
+		return doc, nil
+	}
+	return Apply(seed, update, true)
+}
+
+// seedEqualities : Function copies the equality conditions of query into seed
+func seedEqualities(seed, query bson.M) error {
+	for key, condition := range query {
+		if key == "$and" {
+			clauses, _ := asList(condition)
+			for _, clause := range clauses {
+				if sub, ok := asDocument(clause); ok {
+					if err := seedEqualities(seed, sub); err != nil {
+						return err
+					}
+				}
+			}
+			continue
+		}
+		if strings.HasPrefix(key, "$") {
+			continue
+		}
+		if operators, ok := Operators(condition); ok {
+			value, ok := operators["$eq"]
+			if !ok {
+				continue
+			}
+			condition = value
+		}
+		if err := Set(seed, key, cloneValue(condition)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Project : Function returns the fields of doc selected by fields, with the MongoDB projection rules
+// {"name": 1} keeps name and _id, {"_id": 0, "name": 1} keeps name only, {"password": 0} drops password
+func Project(doc, fields bson.M) bson.M {
+	if len(fields) == 0 {
+		return doc
+	}
+	include := false
+	for key, value := range fields {
+		if key != "_id" && truthy(value) {
+			include = true
+		}
+	}
+
+	if !include {
+		result := Clone(doc)
+		for key := range fields {
+			Unset(result, key)
+		}
+		return result
+	}
+
+	result := bson.M{}
+	if keep, ok := fields["_id"]; !ok || truthy(keep) {
+		if id, ok := doc["_id"]; ok {
+			result["_id"] = id
+		}
+	}
+	for key, value := range fields {
+		if key == "_id" || !truthy(value) {
+			continue
+		}
+		if item, ok := valueAt(doc, key); ok {
+			Set(result, key, cloneValue(item))
+		}
+	}
+	return result
+}
@@ -0,0 +1,126 @@
+package gomongo
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+)
+
+// Manager holds the connections of a service talking to several clusters, by name
+// Configs are registered up front, each connection is opened on its first use and shared afterwards
+type Manager struct {
+	mu      sync.Mutex
+	entries map[string]*managedConnection
+	closed  bool
+}
+
+// managedConnection is a registered Config and its connection once opened
+type managedConnection struct {
+	config *Config
+	conn   *Connection
+	mu     sync.Mutex //serializes the connection of a single name
+}
+
+// NewManager : Function returns an empty Manager
+func NewManager() *Manager {
+	return &Manager{entries: make(map[string]*managedConnection)}
+}
+
+// Register : Function registers the config of a named connection, no connection is made yet
+// Input Parameters
+//		name (string) : name of the connection i.e, "orders"
+//		config (*Config) : config of the connection, validated here
+// Output Parameters
+//		error : ErrorInvalidConfig if config is invalid or name is already registered, ErrorManagerClosed after Close
+func (m *Manager) Register(name string, config *Config) error {
+	if err := config.Validate(); err != nil {
+		return fmt.Errorf("connection %q: %w", name, err)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.closed {
+		return ErrorManagerClosed
+	}
+	if _, ok := m.entries[name]; ok {
+		return fmt.Errorf("%w: connection %q is already registered", ErrorInvalidConfig, name)
+	}
+	m.entries[name] = &managedConnection{config: config}
+	return nil
+}
+
+// Get : Function returns the named connection, connecting it on the first call
+// Every call with the same name returns the same *Connection. A failed connection isn't
+// remembered, the next call tries again
+// Input Parameters
+//		name (string) : name given to Register
+// Output Parameters
+//		*Connection : the shared connection
+//		error : ErrorUnknownConnection if name isn't registered, ErrorManagerClosed after Close, else the connection error
+func (m *Manager) Get(name string) (*Connection, error) {
+	m.mu.Lock()
+	entry, ok := m.entries[name]
+	closed := m.closed
+	m.mu.Unlock()
+	if closed {
+		return nil, ErrorManagerClosed
+	}
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrorUnknownConnection, name)
+	}
+
+	entry.mu.Lock()
+	defer entry.mu.Unlock()
+	if entry.conn != nil {
+		return entry.conn, nil
+	}
+
+	db, err := Init(entry.config.DbType)
+	if err != nil {
+		return nil, err
+	}
+	conn, err := db.Connect(entry.config)
+	if err != nil {
+		log.Println("connection error : ", name, err)
+		return nil, err
+	}
+
+	//Close may have run while connecting
+	m.mu.Lock()
+	closed = m.closed
+	m.mu.Unlock()
+	if closed {
+		Close(conn)
+		return nil, ErrorManagerClosed
+	}
+	entry.conn = conn
+	return conn, nil
+}
+
+// Close : Function closes every opened connection, the Manager can't be used afterwards
+// Output Parameters
+//		error : the errors returned while closing, joined
+func (m *Manager) Close() error {
+	m.mu.Lock()
+	if m.closed {
+		m.mu.Unlock()
+		return nil
+	}
+	m.closed = true
+	entries := m.entries
+	m.mu.Unlock()
+
+	var errs []error
+	for name, entry := range entries {
+		entry.mu.Lock()
+		if entry.conn != nil {
+			if err := Close(entry.conn); err != nil {
+				errs = append(errs, fmt.Errorf("connection %q: %w", name, err))
+			}
+			entry.conn = nil
+		}
+		entry.mu.Unlock()
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,47 @@
+package gomongo
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestManager(t *testing.T) {
+	manager := NewManager()
+	config := Config{DbType: MONGODB, Hosts: TestDBHosts, Database: TestDatabase, Username: TestUserName, Password: TestPassword}
+	assert.Nil(t, manager.Register("orders", &config))
+
+	conn, err := manager.Get("orders")
+	if !assert.Nil(t, err) {
+		return
+	}
+	same, err := manager.Get("orders")
+	assert.Nil(t, err)
+	assert.True(t, conn == same)
+	assert.Nil(t, conn.Ping())
+
+	assert.Nil(t, manager.Close())
+	_, err = manager.Get("orders")
+	assert.True(t, errors.Is(err, ErrorManagerClosed))
+}
+
+func TestManagerRegister(t *testing.T) {
+	manager := NewManager()
+	config := &Config{DbType: MONGODB, Hosts: TestDBHosts, Database: TestDatabase}
+	assert.Nil(t, manager.Register("orders", config))
+
+	err := manager.Register("orders", config)
+	assert.True(t, errors.Is(err, ErrorInvalidConfig))
+
+	err = manager.Register("billing", &Config{DbType: MONGODB, Hosts: TestDBHosts})
+	assert.True(t, errors.Is(err, ErrorInvalidConfig))
+
+	_, err = manager.Get("billing")
+	assert.True(t, errors.Is(err, ErrorUnknownConnection))
+
+	//nothing was connected
+	assert.Nil(t, manager.Close())
+	assert.Nil(t, manager.Close())
+	assert.True(t, errors.Is(manager.Register("billing", config), ErrorManagerClosed))
+}
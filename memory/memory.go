@@ -0,0 +1,440 @@
+// Package memory registers the MEMORY driver of gomongo, which keeps the documents in the memory of
+// the process. It needs no server, so code using gomongo can be unit tested. Import it for its side effect:
+//
+//	import _ "github.com/alishavirani/gomongo/memory"
+//
+//	db, err := gomongo.Init(gomongo.MEMORY)
+//	conn, err := db.Connect(&gomongo.Config{DbType: gomongo.MEMORY, Database: "test"})
+//
+// Every Connect returns an empty database of its own, Reset empties it between tests.
+package memory
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/globalsign/mgo/bson"
+
+	"github.com/alishavirani/gomongo"
+	"github.com/alishavirani/gomongo/internal/document"
+)
+
+func init() {
+	gomongo.Register(gomongo.MEMORY, func() gomongo.DB { return new(Memory) })
+}
+
+// Memory is the DB of the MEMORY driver
+type Memory struct{}
+
+// Connect : Function returns a connection to a new, empty, in memory database
+// Input Parameters
+//		config (*gomongo.Config) : only DbType and Database are used
+// Output Parameters
+//		*gomongo.Connection : connection whose operations are served by the in memory database
+//		error : gomongo.ErrorInvalidDBType if DbType isn't MEMORY
+func (Memory) Connect(config *gomongo.Config) (*gomongo.Connection, error) {
+	if config.DbType != gomongo.MEMORY {
+		return nil, gomongo.ErrorInvalidDBType
+	}
+	return NewConnection(config.Database), nil
+}
+
+// NewConnection : Function returns a connection to a new, empty, in memory database
+// Input Parameters
+//		database (string) : database name reported by Connection.Database
+// Output Parameters
+//		*gomongo.Connection : connection whose operations are served by the in memory database
+func NewConnection(database string) *gomongo.Connection {
+	conn := new(gomongo.Connection)
+	conn.Database = database
+	conn.Store = NewStore()
+	return conn
+}
+
+// Reset : Function drops every collection of the in memory database of conn
+// Input Parameters
+//		conn (*gomongo.Connection) : connection returned by Connect or NewConnection
+// Output Parameters
+//		error : gomongo.ErrorInvalidDBType if conn isn't served by the MEMORY driver
+func Reset(conn *gomongo.Connection) error {
+	store, ok := conn.Store.(*Store)
+	if !ok {
+		return gomongo.ErrorInvalidDBType
+	}
+	store.Reset()
+	return nil
+}
+
+// Store keeps the documents of every collection in insertion order, it is safe for concurrent use
+// The documents are copied on the way in and out, so callers never share them with the Store
+type Store struct {
+	mu          sync.RWMutex
+	collections map[string]*collection
+	closed      bool
+}
+
+// collection holds the documents and the keys of their _id
+type collection struct {
+	docs []bson.M
+	ids  map[string]bool
+}
+
+// NewStore : Function returns an empty Store
+func NewStore() *Store {
+	return &Store{collections: make(map[string]*collection)}
+}
+
+// Reset : Function drops every collection
+func (s *Store) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.collections = make(map[string]*collection)
+}
+
+// Collections : Function returns the number of documents of every collection holding some, i.e. to check a test left nothing behind
+func (s *Store) Collections() map[string]int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	counts := make(map[string]int)
+	for name, c := range s.collections {
+		if len(c.docs) > 0 {
+			counts[name] = len(c.docs)
+		}
+	}
+	return counts
+}
+
+// collection : Function returns the named collection, creating it when create is true, the caller holds the lock
+func (s *Store) collection(name string, create bool) *collection {
+	c, ok := s.collections[name]
+	if !ok && create {
+		c = &collection{ids: make(map[string]bool)}
+		s.collections[name] = c
+	}
+	return c
+}
+
+// Insert : Function inserts docs, none is inserted if one of the _id is taken
+func (s *Store) Insert(ctx context.Context, collectionName string, docs []bson.M) error {
+	keys := make([]string, len(docs))
+	for i, doc := range docs {
+		key, err := idKey(doc["_id"])
+		if err != nil {
+			return err
+		}
+		keys[i] = key
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return gomongo.ErrorNetwork
+	}
+	c := s.collection(collectionName, true)
+	seen := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		if c.ids[key] || seen[key] {
+			return fmt.Errorf("%w: _id %s already exists in %s", gomongo.ErrorDuplicateKey, key, collectionName)
+		}
+		seen[key] = true
+	}
+	for i, doc := range docs {
+		c.docs = append(c.docs, document.Clone(doc))
+		c.ids[keys[i]] = true
+	}
+	return nil
+}
+
+// Find : Function returns copies of the documents matching query in insertion order
+func (s *Store) Find(ctx context.Context, collectionName string, query bson.M, skip, limit int) ([]bson.M, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.closed {
+		return nil, gomongo.ErrorNetwork
+	}
+	c := s.collection(collectionName, false)
+	if c == nil {
+		return nil, nil
+	}
+	indexes, err := c.match(query, skip, limit)
+	if err != nil {
+		return nil, err
+	}
+	docs := make([]bson.M, len(indexes))
+	for i, index := range indexes {
+		docs[i] = document.Clone(c.docs[index])
+	}
+	return docs, nil
+}
+
+// Count : Function returns the number of documents matching query
+func (s *Store) Count(ctx context.Context, collectionName string, query bson.M) (int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.closed {
+		return 0, gomongo.ErrorNetwork
+	}
+	c := s.collection(collectionName, false)
+	if c == nil {
+		return 0, nil
+	}
+	indexes, err := c.match(query, 0, 0)
+	return len(indexes), err
+}
+
+// Distinct : Function returns copies of the distinct values at path of the documents matching query
+func (s *Store) Distinct(ctx context.Context, collectionName string, path string, query bson.M) ([]interface{}, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.closed {
+		return nil, gomongo.ErrorNetwork
+	}
+	c := s.collection(collectionName, false)
+	if c == nil {
+		return []interface{}{}, nil
+	}
+	indexes, err := c.match(query, 0, 0)
+	if err != nil {
+		return nil, err
+	}
+	docs := make([]bson.M, len(indexes))
+	for i, index := range indexes {
+		docs[i] = document.Clone(c.docs[index])
+	}
+	return document.Distinct(docs, path), nil
+}
+
+// Update : Function applies update to the matching documents, all of them are checked before any is changed
+func (s *Store) Update(ctx context.Context, collectionName string, query, update bson.M, multi, upsert bool) (*gomongo.WriteResult, error) {
+	limit := 1
+	if multi {
+		limit = 0
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return nil, gomongo.ErrorNetwork
+	}
+	c := s.collection(collectionName, true)
+	indexes, err := c.match(query, 0, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	info := new(gomongo.WriteResult)
+	if len(indexes) == 0 {
+		if !upsert {
+			return info, nil
+		}
+		doc, err := c.upsert(collectionName, query, update)
+		if err != nil {
+			return nil, err
+		}
+		info.UpsertedId = doc["_id"]
+		return info, nil
+	}
+
+	updated := make([]bson.M, len(indexes))
+	for i, index := range indexes {
+		if updated[i], err = document.Apply(c.docs[index], update, false); err != nil {
+			return nil, fmt.Errorf("%w: %v", gomongo.ErrorValidation, err)
+		}
+	}
+	//the update values are copied, the caller may reuse them
+	for i, index := range indexes {
+		//an update leaving the document as it was matches it without modifying it
+		if !document.Equal(c.docs[index], updated[i]) {
+			c.docs[index] = document.Clone(updated[i])
+			info.Modified++
+		}
+	}
+	info.Matched = len(indexes)
+	return info, nil
+}
+
+// Remove : Function deletes the matching documents
+func (s *Store) Remove(ctx context.Context, collectionName string, query bson.M, multi bool) (*gomongo.WriteResult, error) {
+	limit := 1
+	if multi {
+		limit = 0
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return nil, gomongo.ErrorNetwork
+	}
+	info := new(gomongo.WriteResult)
+	c := s.collection(collectionName, false)
+	if c == nil {
+		return info, nil
+	}
+	indexes, err := c.match(query, 0, limit)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.remove(indexes); err != nil {
+		return nil, err
+	}
+	info.Matched, info.Removed = len(indexes), len(indexes)
+	return info, nil
+}
+
+// FindAndModify : Function changes or removes the first document matching query in the order of sort,
+// holding the lock of the Store from the find to the change
+func (s *Store) FindAndModify(ctx context.Context, collectionName string, query bson.M, sort bson.D, update bson.M, upsert bool) (bson.M, bson.M, error) {
+	var keys []document.SortKey
+	if len(sort) > 0 {
+		var err error
+		if keys, err = document.ParseSort(sort); err != nil {
+			return nil, nil, fmt.Errorf("%w: %v", gomongo.ErrorValidation, err)
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return nil, nil, gomongo.ErrorNetwork
+	}
+	c := s.collection(collectionName, true)
+	indexes, err := c.match(query, 0, 0)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(indexes) == 0 {
+		if update == nil || !upsert {
+			return nil, nil, nil
+		}
+		doc, err := c.upsert(collectionName, query, update)
+		if err != nil {
+			return nil, nil, err
+		}
+		return nil, doc, nil
+	}
+
+	docs := make([]bson.M, len(indexes))
+	for i, index := range indexes {
+		docs[i] = c.docs[index]
+	}
+	index := indexes[document.First(docs, keys)]
+	//the document replaced or removed isn't referenced by the Store anymore, it is returned as is
+	before := c.docs[index]
+	if update == nil {
+		if err := c.remove([]int{index}); err != nil {
+			return nil, nil, err
+		}
+		return before, nil, nil
+	}
+	after, err := document.Apply(before, update, false)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", gomongo.ErrorValidation, err)
+	}
+	c.docs[index] = document.Clone(after)
+	return before, after, nil
+}
+
+// Ping : Function fails once the Store is closed
+func (s *Store) Ping(ctx context.Context) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.closed {
+		return gomongo.ErrorNetwork
+	}
+	return nil
+}
+
+// Close : Function drops every collection, the Store can't be used afterwards
+func (s *Store) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+	s.collections = make(map[string]*collection)
+	return nil
+}
+
+// upsert : Function inserts the document built from query and update, returning a copy of it
+func (c *collection) upsert(collectionName string, query, update bson.M) (bson.M, error) {
+	doc, err := document.Upserted(query, update)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", gomongo.ErrorValidation, err)
+	}
+	if _, ok := doc["_id"]; !ok {
+		doc["_id"] = bson.NewObjectId()
+	}
+	key, err := idKey(doc["_id"])
+	if err != nil {
+		return nil, err
+	}
+	if c.ids[key] {
+		return nil, fmt.Errorf("%w: _id %s already exists in %s", gomongo.ErrorDuplicateKey, key, collectionName)
+	}
+	c.docs = append(c.docs, document.Clone(doc))
+	c.ids[key] = true
+	return doc, nil
+}
+
+// remove : Function deletes the documents at indexes
+func (c *collection) remove(indexes []int) error {
+	keys := make([]string, len(indexes))
+	for i, index := range indexes {
+		var err error
+		if keys[i], err = idKey(c.docs[index]["_id"]); err != nil {
+			return err
+		}
+	}
+	removed := make(map[int]bool, len(indexes))
+	for i, index := range indexes {
+		removed[index] = true
+		delete(c.ids, keys[i])
+	}
+	kept := c.docs[:0]
+	for i, doc := range c.docs {
+		if !removed[i] {
+			kept = append(kept, doc)
+		}
+	}
+	//drop the references left at the end of the backing array
+	for i := len(kept); i < len(c.docs); i++ {
+		c.docs[i] = nil
+	}
+	c.docs = kept
+	return nil
+}
+
+// match : Function returns the indexes of the documents matching query
+func (c *collection) match(query bson.M, skip, limit int) ([]int, error) {
+	var indexes []int
+	for i, doc := range c.docs {
+		matched, err := document.Match(doc, query)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", gomongo.ErrorValidation, err)
+		}
+		if !matched {
+			continue
+		}
+		if skip > 0 {
+			skip--
+			continue
+		}
+		indexes = append(indexes, i)
+		if limit > 0 && len(indexes) == limit {
+			break
+		}
+	}
+	return indexes, nil
+}
+
+// idKey : Function returns the key of an _id, numbers being keyed the same whatever their Go type
+func idKey(id interface{}) (string, error) {
+	if n, ok := document.Number(id); ok {
+		id = n
+	}
+	data, err := bson.MarshalJSON(id)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", gomongo.ErrorValidation, err)
+	}
+	return string(bytes.TrimSpace(data)), nil
+}
@@ -0,0 +1,612 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	mgo "github.com/globalsign/mgo"
+	"github.com/globalsign/mgo/bson"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/alishavirani/gomongo"
+)
+
+type Person struct {
+	Id        bson.ObjectId `bson:"_id,omitempty"`
+	FirstName string        `bson:"firstName"`
+	Age       int           `bson:"age"`
+}
+
+func connect(t *testing.T) *gomongo.Connection {
+	db, err := gomongo.Init(gomongo.MEMORY)
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+	conn, err := db.Connect(&gomongo.Config{DbType: gomongo.MEMORY, Database: "test"})
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+	t.Cleanup(func() { gomongo.Close(conn) })
+	return conn
+}
+
+func seed(t *testing.T, users *gomongo.Collection) {
+	_, err := users.BulkInsert(&gomongo.BulkInsertStruct{Data: []interface{}{
+		Person{FirstName: "Amulya", Age: 26},
+		Person{FirstName: "Kasyap", Age: 31},
+		bson.M{"_id": 7, "firstName": "Ravi", "age": 40, "tags": []string{"admin"}},
+	}})
+	assert.Nil(t, err)
+}
+
+func TestInsertFind(t *testing.T) {
+	users := connect(t).C("users")
+	seed(t, users)
+
+	records, err := users.Find(&gomongo.FindStruct{Query: bson.M{"age": bson.M{"$gt": 30}}, Options: map[string]int{"isSkip": 1}})
+	assert.Nil(t, err)
+	if assert.Len(t, records, 1) {
+		assert.Equal(t, "Ravi", records[0].(bson.M)["firstName"])
+	}
+
+	records, err = users.Find(&gomongo.FindStruct{Query: bson.M{"tags": "admin"}, Fields: bson.M{"firstName": 1}})
+	assert.Nil(t, err)
+	if assert.Len(t, records, 1) {
+		assert.Equal(t, bson.M{"_id": 7, "firstName": "Ravi"}, records[0])
+	}
+
+	//records are copies, changing them leaves the stored documents untouched
+	record, err := users.FindByID(&gomongo.FindByIDStruct{Id: 7})
+	assert.Nil(t, err)
+	record.(bson.M)["firstName"] = "Changed"
+	record, err = users.FindByID(&gomongo.FindByIDStruct{Id: 7})
+	assert.Nil(t, err)
+	assert.Equal(t, "Ravi", record.(bson.M)["firstName"])
+
+	_, err = users.FindByID(&gomongo.FindByIDStruct{Id: 8})
+	assert.True(t, errors.Is(err, gomongo.ErrorNotFound))
+
+	all, err := connect(t).C("users").FindAll(&gomongo.FindAllStruct{})
+	assert.Nil(t, err)
+	assert.Empty(t, all, "every connection has a database of its own")
+}
+
+func TestFindOptions(t *testing.T) {
+	users := connect(t).C("users")
+	seed(t, users)
+	_, err := users.Insert(&gomongo.InsertStruct{Data: Person{FirstName: "Anu", Age: 31}})
+	assert.Nil(t, err)
+	names := func(records []interface{}) []interface{} {
+		var names []interface{}
+		for _, record := range records {
+			names = append(names, record.(bson.M)["firstName"])
+		}
+		return names
+	}
+
+	records, err := users.FindAll(&gomongo.FindAllStruct{FindOptions: gomongo.FindOptions{Sort: []string{"-age", "firstName"}}})
+	assert.Nil(t, err)
+	assert.Equal(t, []interface{}{"Ravi", "Anu", "Kasyap", "Amulya"}, names(records))
+
+	records, err = users.Find(&gomongo.FindStruct{Query: bson.M{"age": bson.M{"$gt": 20}}, FindOptions: gomongo.FindOptions{Sort: []string{"age"}, Skip: 1, Limit: 2}})
+	assert.Nil(t, err)
+	assert.Equal(t, []interface{}{"Kasyap", "Anu"}, names(records), "skip and limit apply after the sort")
+
+	records, err = users.Find(&gomongo.FindStruct{Options: map[string]int{"skip": 2}})
+	assert.Nil(t, err)
+	assert.Equal(t, []interface{}{"Ravi", "Anu"}, names(records))
+
+	_, err = users.Find(&gomongo.FindStruct{FindOptions: gomongo.FindOptions{Sort: []string{"-"}}})
+	assert.True(t, errors.Is(err, gomongo.ErrorValidation))
+}
+
+func TestFindCursor(t *testing.T) {
+	users := connect(t).C("users")
+	seed(t, users)
+
+	cursor, err := users.FindCursor(&gomongo.FindStruct{Query: bson.M{"age": bson.M{"$gt": 20}}, FindOptions: gomongo.FindOptions{Sort: []string{"-age"}}})
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+	var people []Person
+	for cursor.Next() {
+		var person Person
+		assert.Nil(t, cursor.Decode(&person))
+		people = append(people, person)
+	}
+	assert.Nil(t, cursor.Err())
+	assert.Nil(t, cursor.Close())
+	if assert.Len(t, people, 3) {
+		assert.Equal(t, []int{40, 31, 26}, []int{people[0].Age, people[1].Age, people[2].Age})
+	}
+	assert.False(t, cursor.Next(), "a closed cursor has no record left")
+	assert.True(t, errors.Is(cursor.Decode(new(Person)), gomongo.ErrorValidation))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cursor, err = users.FindCursorCtx(ctx, &gomongo.FindStruct{})
+	assert.Nil(t, err)
+	assert.True(t, cursor.Next())
+	assert.True(t, errors.Is(cursor.Decode(Person{}), gomongo.ErrorValidation), "Decode needs a pointer")
+	cancel()
+	assert.False(t, cursor.Next())
+	assert.True(t, errors.Is(cursor.Err(), context.Canceled))
+}
+
+func TestFindStream(t *testing.T) {
+	users := connect(t).C("users")
+	seed(t, users)
+
+	callback := make(chan *gomongo.Callback)
+	go users.FindStream(context.Background(), &gomongo.FindStruct{FindOptions: gomongo.FindOptions{Sort: []string{"firstName"}}}, callback)
+	var names []interface{}
+	for cb := range callback {
+		assert.Nil(t, cb.Error)
+		names = append(names, cb.Data.(bson.M)["firstName"])
+	}
+	assert.Equal(t, []interface{}{"Amulya", "Kasyap", "Ravi"}, names)
+
+	callback = make(chan *gomongo.Callback)
+	go users.FindStream(context.Background(), &gomongo.FindStruct{FindOptions: gomongo.FindOptions{Skip: -1}}, callback)
+	cb := <-callback
+	assert.True(t, errors.Is(cb.Error, gomongo.ErrorValidation))
+	_, open := <-callback
+	assert.False(t, open)
+
+	//a reader giving up cancels the context, the stream stops instead of blocking
+	ctx, cancel := context.WithCancel(context.Background())
+	callback = make(chan *gomongo.Callback)
+	done := make(chan struct{})
+	go func() {
+		users.FindStream(ctx, &gomongo.FindStruct{}, callback)
+		close(done)
+	}()
+	<-callback
+	cancel()
+	<-done
+}
+
+func TestFindPage(t *testing.T) {
+	orders := connect(t).C("orders")
+	for i := 1; i <= 5; i++ {
+		_, err := orders.Insert(&gomongo.InsertStruct{Data: bson.M{"_id": i, "total": i % 3}})
+		assert.Nil(t, err)
+	}
+
+	ids := func(page *gomongo.Page) []interface{} {
+		var ids []interface{}
+		for _, record := range page.Records {
+			ids = append(ids, record.(bson.M)["_id"])
+		}
+		return ids
+	}
+
+	page, err := orders.FindPage(&gomongo.PageStruct{Limit: 2})
+	assert.Nil(t, err)
+	assert.Equal(t, []interface{}{1, 2}, ids(page))
+
+	//a record inserted before the position of the token isn't returned again
+	_, err = orders.Insert(&gomongo.InsertStruct{Data: bson.M{"_id": 0, "total": 0}})
+	assert.Nil(t, err)
+	page, err = orders.FindPage(&gomongo.PageStruct{Limit: 2, Token: page.NextToken})
+	assert.Nil(t, err)
+	assert.Equal(t, []interface{}{3, 4}, ids(page))
+	page, err = orders.FindPage(&gomongo.PageStruct{Limit: 2, Token: page.NextToken})
+	assert.Nil(t, err)
+	assert.Equal(t, []interface{}{5}, ids(page))
+	assert.Empty(t, page.NextToken)
+
+	//records sharing a total are ordered on _id, descending as well
+	var all []interface{}
+	page = &gomongo.Page{}
+	for {
+		page, err = orders.FindPage(&gomongo.PageStruct{Query: bson.M{"_id": bson.M{"$gt": 0}}, Fields: bson.M{"_id": 1}, Sort: "-total", Limit: 2, Token: page.NextToken})
+		assert.Nil(t, err)
+		all = append(all, ids(page)...)
+		if page.NextToken == "" {
+			break
+		}
+	}
+	assert.Equal(t, []interface{}{5, 2, 4, 1, 3}, all)
+
+	_, err = orders.FindPage(&gomongo.PageStruct{Token: "not a token"})
+	assert.True(t, errors.Is(err, gomongo.ErrorValidation))
+	page, err = orders.FindPage(&gomongo.PageStruct{Limit: 1})
+	assert.Nil(t, err)
+	_, err = orders.FindPage(&gomongo.PageStruct{Sort: "total", Token: page.NextToken})
+	assert.True(t, errors.Is(err, gomongo.ErrorValidation), "a token is bound to its sort")
+	_, err = orders.FindPage(&gomongo.PageStruct{Query: bson.M{"total": 1}, Token: page.NextToken})
+	assert.True(t, errors.Is(err, gomongo.ErrorValidation), "a token is bound to its query")
+
+	//the records without a total, null or missing, come first ascending and last descending
+	_, err = orders.BulkInsert(&gomongo.BulkInsertStruct{Data: []interface{}{bson.M{"_id": 6, "total": nil}, bson.M{"_id": 7}}})
+	assert.Nil(t, err)
+	listing := func(sort string) []interface{} {
+		var all []interface{}
+		page := &gomongo.Page{}
+		for {
+			page, err = orders.FindPage(&gomongo.PageStruct{Query: bson.M{"_id": bson.M{"$in": []interface{}{1, 3, 6, 7}}}, Sort: sort, Limit: 1, Token: page.NextToken})
+			assert.Nil(t, err)
+			all = append(all, ids(page)...)
+			if page.NextToken == "" {
+				return all
+			}
+		}
+	}
+	assert.Equal(t, []interface{}{6, 7, 3, 1}, listing("total"))
+	assert.Equal(t, []interface{}{1, 3, 7, 6}, listing("-total"))
+}
+
+func TestCountDistinctExists(t *testing.T) {
+	users := connect(t).C("users")
+	seed(t, users)
+
+	count, err := users.Count(&gomongo.CountStruct{Query: bson.M{"age": bson.M{"$gt": 30}}})
+	assert.Nil(t, err)
+	assert.Equal(t, 2, count)
+	count, err = users.Count(&gomongo.CountStruct{Estimated: true})
+	assert.Nil(t, err)
+	assert.Equal(t, 3, count)
+	_, err = users.Count(&gomongo.CountStruct{Query: bson.M{"age": 26}, Estimated: true})
+	assert.True(t, errors.Is(err, gomongo.ErrorValidation))
+
+	_, err = users.Insert(&gomongo.InsertStruct{Data: bson.M{"firstName": "Ravi", "tags": []string{"admin", "ops"}}})
+	assert.Nil(t, err)
+	names, err := users.Distinct(&gomongo.DistinctStruct{Field: "firstName"})
+	assert.Nil(t, err)
+	assert.ElementsMatch(t, []interface{}{"Amulya", "Kasyap", "Ravi"}, names)
+	tags, err := users.Distinct(&gomongo.DistinctStruct{Field: "tags", Query: bson.M{"firstName": "Ravi"}})
+	assert.Nil(t, err)
+	assert.ElementsMatch(t, []interface{}{"admin", "ops"}, tags)
+	_, err = users.Distinct(&gomongo.DistinctStruct{})
+	assert.True(t, errors.Is(err, gomongo.ErrorValidation))
+
+	exists, err := users.Exists(&gomongo.ExistsStruct{Query: bson.M{"firstName": "Kasyap"}})
+	assert.Nil(t, err)
+	assert.True(t, exists)
+	exists, err = users.Exists(&gomongo.ExistsStruct{Query: bson.M{"firstName": "Nobody"}})
+	assert.Nil(t, err)
+	assert.False(t, exists)
+
+	callback := make(chan *gomongo.Callback)
+	go users.CountAsync(&gomongo.CountStruct{}, callback)
+	cb := <-callback
+	assert.Nil(t, cb.Error)
+	assert.Equal(t, 4, cb.Data)
+}
+
+func TestArrayPaths(t *testing.T) {
+	orders := connect(t).C("orders")
+	_, err := orders.BulkInsert(&gomongo.BulkInsertStruct{Data: []interface{}{
+		bson.M{"_id": 1, "items": []interface{}{bson.M{"name": "pen", "price": 5}, bson.M{"name": "ink", "price": 1}}},
+		bson.M{"_id": 2, "items": []interface{}{bson.M{"name": "pad", "price": 3}}},
+		bson.M{"_id": 3, "items": bson.M{"name": "ink", "price": 2}},
+	}})
+	assert.Nil(t, err)
+
+	//a dotted path goes into the documents of the arrays it crosses, like MongoDB does
+	count, err := orders.Count(&gomongo.CountStruct{Query: bson.M{"items.name": "ink"}})
+	assert.Nil(t, err)
+	assert.Equal(t, 2, count)
+	names, err := orders.Distinct(&gomongo.DistinctStruct{Field: "items.name"})
+	assert.Nil(t, err)
+	assert.ElementsMatch(t, []interface{}{"pen", "ink", "pad"}, names)
+
+	records, err := orders.Find(&gomongo.FindStruct{Query: bson.M{"items.price": bson.M{"$gte": 2}}, FindOptions: gomongo.FindOptions{Sort: []string{"items.price"}}})
+	assert.Nil(t, err)
+	var ids []interface{}
+	for _, record := range records {
+		ids = append(ids, record.(bson.M)["_id"])
+	}
+	assert.Equal(t, []interface{}{1, 3, 2}, ids, "an array is sorted on its smallest element")
+}
+
+func TestAggregate(t *testing.T) {
+	conn := connect(t)
+	users := conn.C("users")
+	seed(t, users)
+	_, err := conn.C("teams").BulkInsert(&gomongo.BulkInsertStruct{Data: []interface{}{
+		bson.M{"_id": "core", "members": []string{"Amulya", "Ravi"}},
+		bson.M{"_id": "ops", "members": []string{"Ravi"}},
+	}})
+	assert.Nil(t, err)
+
+	records, err := users.Aggregate(&gomongo.AggregateStruct{Pipeline: gomongo.Pipeline{}.
+		Match(bson.M{"age": bson.M{"$gt": 20}}).
+		Lookup("teams", "firstName", "members", "teams").
+		Unwind("teams", false).
+		Group("$teams._id", bson.M{"members": bson.M{"$sum": 1}, "oldest": bson.M{"$max": "$age"}}).
+		Sort("-members", "_id"),
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, []interface{}{
+		bson.M{"_id": "core", "members": 2, "oldest": 40},
+		bson.M{"_id": "ops", "members": 1, "oldest": 40},
+	}, records)
+
+	base := gomongo.Pipeline{}.Sort("age")
+	records, err = users.Aggregate(&gomongo.AggregateStruct{Pipeline: base.Facet(map[string]gomongo.Pipeline{
+		"youngest": gomongo.Pipeline{}.Limit(1).Project(bson.M{"_id": 0, "name": "$firstName"}),
+		"others":   gomongo.Pipeline{}.Skip(1).Stage(bson.M{"$count": "n"}),
+	})})
+	assert.Nil(t, err)
+	assert.Equal(t, []interface{}{bson.M{
+		"youngest": []interface{}{bson.M{"name": "Amulya"}},
+		"others":   []interface{}{bson.M{"n": 2}},
+	}}, records)
+	assert.Len(t, base, 1, "building on a pipeline leaves it untouched")
+
+	cursor, err := users.AggregateCursor(&gomongo.AggregateStruct{Pipeline: gomongo.Pipeline{}.Sort("-age"), BatchSize: 1})
+	assert.Nil(t, err)
+	var ages []int
+	for cursor.Next() {
+		var person Person
+		assert.Nil(t, cursor.Decode(&person))
+		ages = append(ages, person.Age)
+	}
+	assert.Nil(t, cursor.Err())
+	assert.Nil(t, cursor.Close())
+	assert.Equal(t, []int{40, 31, 26}, ages)
+
+	callback := make(chan *gomongo.Callback)
+	go users.AggregateStream(context.Background(), &gomongo.AggregateStruct{Pipeline: gomongo.Pipeline{}.Stage(bson.M{"$out": "copy"})}, callback)
+	cb := <-callback
+	assert.NotNil(t, cb.Error, "the stages the drivers don't run are reported")
+
+	_, err = users.Aggregate(&gomongo.AggregateStruct{Pipeline: []bson.M{{"$match": bson.M{}, "$limit": 1}}})
+	assert.True(t, errors.Is(err, gomongo.ErrorValidation))
+}
+
+func TestFindOneAndModify(t *testing.T) {
+	jobs := connect(t).C("jobs")
+	for i := 1; i <= 20; i++ {
+		_, err := jobs.Insert(&gomongo.InsertStruct{Data: bson.M{"_id": i, "status": "pending", "priority": i % 4}})
+		assert.Nil(t, err)
+	}
+
+	before, err := jobs.FindOneAndUpdate(&gomongo.FindOneAndUpdateStruct{
+		Query: bson.M{"status": "pending"}, Data: bson.M{"$set": bson.M{"status": "running"}}, Sort: []string{"-priority", "_id"}, Fields: bson.M{"status": 1}})
+	assert.Nil(t, err)
+	assert.Equal(t, bson.M{"_id": 3, "status": "pending"}, before)
+	after, err := jobs.FindOneAndUpdate(&gomongo.FindOneAndUpdateStruct{
+		Query: bson.M{"status": "pending"}, Data: bson.M{"$set": bson.M{"status": "running"}}, Sort: []string{"-priority", "_id"}, ReturnNew: true})
+	assert.Nil(t, err)
+	assert.Equal(t, bson.M{"_id": 7, "status": "running", "priority": 3}, after)
+
+	//every pending job is claimed exactly once by the racing workers
+	var mu sync.Mutex
+	claimed := map[interface{}]int{}
+	var wg sync.WaitGroup
+	for w := 0; w < 8; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				job, err := jobs.FindOneAndUpdate(&gomongo.FindOneAndUpdateStruct{Query: bson.M{"status": "pending"}, Data: bson.M{"$set": bson.M{"status": "running"}}})
+				if errors.Is(err, gomongo.ErrorNotFound) {
+					return
+				}
+				assert.Nil(t, err)
+				mu.Lock()
+				claimed[job.(bson.M)["_id"]]++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Len(t, claimed, 18)
+	for id, n := range claimed {
+		assert.Equal(t, 1, n, "job %v", id)
+	}
+
+	replaced, err := jobs.FindOneAndReplace(&gomongo.FindOneAndReplaceStruct{Query: bson.M{"_id": 1}, Data: bson.M{"status": "done"}, ReturnNew: true})
+	assert.Nil(t, err)
+	assert.Equal(t, bson.M{"_id": 1, "status": "done"}, replaced)
+	inserted, err := jobs.FindOneAndReplace(&gomongo.FindOneAndReplaceStruct{Query: bson.M{"_id": 30}, Data: bson.M{"status": "new"}, Upsert: true})
+	assert.Nil(t, err)
+	assert.Nil(t, inserted, "an upserted record has no previous version")
+	inserted, err = jobs.FindOneAndUpdate(&gomongo.FindOneAndUpdateStruct{Query: bson.M{"_id": 31}, Data: bson.M{"$set": bson.M{"status": "new"}}, Upsert: true, ReturnNew: true})
+	assert.Nil(t, err)
+	assert.Equal(t, bson.M{"_id": 31, "status": "new"}, inserted)
+
+	removed, err := jobs.FindOneAndDelete(&gomongo.FindOneAndDeleteStruct{Query: bson.M{"status": "new"}, Sort: []string{"-_id"}})
+	assert.Nil(t, err)
+	assert.Equal(t, bson.M{"_id": 31, "status": "new"}, removed)
+	exists, err := jobs.Exists(&gomongo.ExistsStruct{Query: bson.M{"_id": 31}})
+	assert.Nil(t, err)
+	assert.False(t, exists)
+
+	_, err = jobs.FindOneAndDelete(&gomongo.FindOneAndDeleteStruct{Query: bson.M{"status": "missing"}})
+	assert.True(t, errors.Is(err, gomongo.ErrorNotFound))
+	_, err = jobs.FindOneAndUpdate(&gomongo.FindOneAndUpdateStruct{Query: bson.M{"_id": 2}, Data: bson.M{"status": "done"}})
+	assert.True(t, errors.Is(err, gomongo.ErrorValidation))
+	_, err = jobs.FindOneAndReplace(&gomongo.FindOneAndReplaceStruct{Query: bson.M{"_id": 2}, Data: bson.M{"$set": bson.M{"status": "done"}}})
+	assert.True(t, errors.Is(err, gomongo.ErrorValidation))
+}
+
+func TestDuplicateKey(t *testing.T) {
+	users := connect(t).C("users")
+	seed(t, users)
+
+	_, err := users.Insert(&gomongo.InsertStruct{Data: bson.M{"_id": 7.0}})
+	assert.True(t, errors.Is(err, gomongo.ErrorDuplicateKey))
+
+	_, err = users.BulkInsert(&gomongo.BulkInsertStruct{Data: []interface{}{bson.M{"_id": "a"}, bson.M{"_id": "a"}}})
+	assert.True(t, errors.Is(err, gomongo.ErrorDuplicateKey))
+	all, err := users.FindAll(&gomongo.FindAllStruct{})
+	assert.Nil(t, err)
+	assert.Len(t, all, 3, "a failed insert inserts nothing")
+}
+
+func TestUpdateRemove(t *testing.T) {
+	users := connect(t).C("users")
+	seed(t, users)
+
+	_, err := users.Update(&gomongo.UpdateStruct{Id: gomongo.StringID("missing"), Data: bson.M{"$set": bson.M{"age": 1}}})
+	assert.True(t, errors.Is(err, gomongo.ErrorNotFound))
+
+	info, err := users.UpdateAll(gomongo.UpdateAllStruct{Query: bson.M{"age": bson.M{"$lt": 35}}, Data: bson.M{"$inc": bson.M{"age": 1}, "$push": bson.M{"tags": "new"}}})
+	assert.Nil(t, err)
+	assert.Equal(t, 2, info.Modified)
+
+	//setting the values a record already has matches it without modifying it
+	info, err = users.UpdateAll(gomongo.UpdateAllStruct{Query: bson.M{"firstName": "Ravi"}, Data: bson.M{"$set": bson.M{"firstName": "Ravi"}}})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, info.Matched)
+	assert.Equal(t, 0, info.Modified)
+
+	_, err = users.UpdateOne(gomongo.UpdateOneStruct{Query: bson.M{"_id": 7}, Data: bson.M{"$set": bson.M{"_id": 8}}})
+	assert.True(t, errors.Is(err, gomongo.ErrorValidation))
+
+	info, err = users.Upsert(&gomongo.UpsertStruct{Id: gomongo.StringID("anu"), Data: bson.M{"$set": bson.M{"firstName": "Anu"}, "$setOnInsert": bson.M{"age": 22}}})
+	assert.Nil(t, err)
+	assert.Equal(t, "anu", info.UpsertedId)
+
+	records, err := users.Find(&gomongo.FindStruct{Query: bson.M{"$or": []interface{}{bson.M{"tags": "new"}, bson.M{"age": 22}}}})
+	assert.Nil(t, err)
+	assert.Len(t, records, 3)
+
+	_, err = users.Remove(&gomongo.RemoveStruct{Query: bson.M{"firstName": "Nobody"}})
+	assert.True(t, errors.Is(err, gomongo.ErrorNotFound))
+
+	info, err = users.Remove(&gomongo.RemoveStruct{Query: bson.M{"_id": 7}})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, info.Removed)
+	_, err = users.Insert(&gomongo.InsertStruct{Data: bson.M{"_id": 7}})
+	assert.Nil(t, err, "a removed _id can be reused")
+
+	info, err = users.RemoveAll(&gomongo.RemoveAllStruct{})
+	assert.Nil(t, err)
+	assert.Equal(t, 4, info.Removed)
+}
+
+func TestWriteResult(t *testing.T) {
+	users := connect(t).C("users")
+
+	result, err := users.Insert(&gomongo.InsertStruct{Data: Person{FirstName: "Amulya"}})
+	assert.Nil(t, err)
+	if assert.Len(t, result.InsertedIds, 1) {
+		id, ok := result.InsertedIds[0].(bson.ObjectId)
+		assert.True(t, ok && id.Valid())
+		_, err = users.FindByID(&gomongo.FindByIDStruct{Id: id})
+		assert.Nil(t, err, "the returned _id is the one stored")
+	}
+
+	result, err = users.BulkInsert(&gomongo.BulkInsertStruct{Data: []interface{}{bson.M{"_id": 7}, Person{FirstName: "Kasyap"}}})
+	assert.Nil(t, err)
+	if assert.Len(t, result.InsertedIds, 2) {
+		assert.Equal(t, 7, result.InsertedIds[0])
+		assert.IsType(t, bson.ObjectId(""), result.InsertedIds[1])
+	}
+
+	result, err = users.Update(&gomongo.UpdateStruct{Id: 7, Data: bson.M{"$set": bson.M{"age": 40}}})
+	assert.Nil(t, err)
+	assert.Equal(t, &gomongo.WriteResult{Matched: 1, Modified: 1}, result)
+
+	result, err = users.Upsert(&gomongo.UpsertStruct{Id: 8, Data: bson.M{"$set": bson.M{"age": 41}}})
+	assert.Nil(t, err)
+	assert.Equal(t, &gomongo.WriteResult{UpsertedId: 8}, result)
+
+	result, err = users.Remove(&gomongo.RemoveStruct{Query: bson.M{"age": 41}})
+	assert.Nil(t, err)
+	assert.Equal(t, &gomongo.WriteResult{Matched: 1, Removed: 1}, result)
+}
+
+func TestReset(t *testing.T) {
+	conn := connect(t)
+	seed(t, conn.C("users"))
+	assert.Equal(t, map[string]int{"users": 3}, conn.Store.(*Store).Collections())
+
+	assert.Nil(t, Reset(conn))
+	assert.Empty(t, conn.Store.(*Store).Collections())
+
+	assert.True(t, errors.Is(Reset(new(gomongo.Connection)), gomongo.ErrorInvalidDBType))
+}
+
+func TestConcurrentUse(t *testing.T) {
+	counters := connect(t).C("counters")
+	_, err := counters.Insert(&gomongo.InsertStruct{Data: bson.M{"_id": "hits", "n": 0}})
+	assert.Nil(t, err)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, err := counters.UpdateOne(gomongo.UpdateOneStruct{Query: bson.M{"_id": "hits"}, Data: bson.M{"$inc": bson.M{"n": 1}}})
+			assert.Nil(t, err)
+			_, err = counters.Insert(&gomongo.InsertStruct{Data: bson.M{"_id": fmt.Sprint(i)}})
+			assert.Nil(t, err)
+			_, err = counters.FindAll(&gomongo.FindAllStruct{})
+			assert.Nil(t, err)
+		}(i)
+	}
+	wg.Wait()
+
+	record, err := counters.FindByID(&gomongo.FindByIDStruct{Id: gomongo.StringID("hits")})
+	assert.Nil(t, err)
+	assert.Equal(t, 20, record.(bson.M)["n"])
+}
+
+func TestClose(t *testing.T) {
+	conn := connect(t)
+	assert.True(t, conn.Healthy())
+	assert.Nil(t, gomongo.Close(conn))
+	assert.False(t, conn.Healthy())
+	_, err := conn.C("users").Insert(&gomongo.InsertStruct{Data: bson.M{}})
+	assert.True(t, errors.Is(err, gomongo.ErrorNetwork))
+}
+
+// countingOps counts the inserts going through the wrapped Operations
+type countingOps struct {
+	gomongo.Operations
+	inserts int
+}
+
+func (c *countingOps) Insert(insertStruct *gomongo.InsertStruct) (*gomongo.WriteResult, error) {
+	c.inserts++
+	return c.Operations.Insert(insertStruct)
+}
+
+func TestOperationsWrapper(t *testing.T) {
+	conn := connect(t)
+	conn.Collection = "users"
+	var ops gomongo.Operations = &countingOps{Operations: conn}
+
+	_, err := ops.Insert(&gomongo.InsertStruct{Data: bson.M{"firstName": "Amulya"}})
+	assert.Nil(t, err)
+	records, err := ops.FindAll(&gomongo.FindAllStruct{})
+	assert.Nil(t, err)
+	assert.Len(t, records, 1)
+	assert.Equal(t, 1, ops.(*countingOps).inserts)
+
+	//the operations added since V3 reach the wrapped connection as well
+	count, err := ops.Count(&gomongo.CountStruct{})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, count)
+}
+
+func TestOperationsV2(t *testing.T) {
+	users := connect(t).C("users")
+	var ops gomongo.OperationsV2 = gomongo.AsOperationsV2(users)
+
+	assert.Nil(t, ops.Insert(&gomongo.InsertStruct{Data: bson.M{"_id": 1, "age": 20}}))
+	info, err := ops.UpdateAll(gomongo.UpdateAllStruct{Query: bson.M{}, Data: bson.M{"$inc": bson.M{"age": 1}}})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, info.Matched)
+	assert.Equal(t, 1, info.Updated)
+	info, err = ops.Upsert(&gomongo.UpsertStruct{Id: 2, Data: bson.M{"$set": bson.M{"age": 30}}})
+	assert.Nil(t, err)
+	assert.Equal(t, 2, info.UpsertedId)
+	bulk, err := ops.BulkInsert(&gomongo.BulkInsertStruct{Data: []interface{}{bson.M{"_id": 3}}})
+	assert.Nil(t, err)
+	assert.Equal(t, &mgo.BulkResult{}, bulk)
+
+	callback := make(chan *gomongo.Callback)
+	go ops.RemoveAllAsync(&gomongo.RemoveAllStruct{}, callback)
+	cb := <-callback
+	assert.Nil(t, cb.Error)
+	assert.Equal(t, 3, cb.Data.(*mgo.ChangeInfo).Removed)
+}
@@ -0,0 +1,477 @@
+// Package mysql registers the MYSQL driver of gomongo, which keeps the documents of each collection
+// in a JSON column of a MySQL (8.0.17+) table and translates the queries and updates into SQL.
+// Import it for its side effect:
+//
+//	import _ "github.com/alishavirani/gomongo/mysql"
+//
+//	db, err := gomongo.Init(gomongo.MYSQL)
+//	conn, err := db.Connect(&gomongo.Config{DbType: gomongo.MYSQL, Hosts: "localhost:3306", Database: "app", Username: "app", Password: "secret"})
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/globalsign/mgo/bson"
+	mysqldriver "github.com/go-sql-driver/mysql"
+
+	"github.com/alishavirani/gomongo"
+	"github.com/alishavirani/gomongo/internal/document"
+)
+
+// errDuplicateEntry is the MySQL error number of a violated unique key
+const errDuplicateEntry = 1062
+
+func init() {
+	gomongo.Register(gomongo.MYSQL, func() gomongo.DB { return new(MySQL) })
+}
+
+// MySQL is the DB of the MYSQL driver
+type MySQL struct{}
+
+// Connect : Function opens the MySQL database of config
+// Input Parameters
+//		config (*gomongo.Config) : Uri is a data source name of the go-sql-driver (i.e, "app:secret@tcp(localhost:3306)/app"),
+//			else the first of Hosts, Database, Username and Password are used. DialTimeout, SocketTimeout and PoolLimit apply
+// Output Parameters
+//		*gomongo.Connection : connection whose operations are served by the database
+//		error : gomongo.ErrorInvalidConfig if the database can't be told, else the error of the MySQL driver
+func (MySQL) Connect(config *gomongo.Config) (*gomongo.Connection, error) {
+	if config.DbType != gomongo.MYSQL {
+		return nil, gomongo.ErrorInvalidDBType
+	}
+	dsn, err := dataSourceName(config)
+	if err != nil {
+		return nil, err
+	}
+
+	db, err := sql.Open("mysql", dsn)
+	if err != nil {
+		return nil, err
+	}
+	if config.PoolLimit > 0 {
+		db.SetMaxOpenConns(config.PoolLimit)
+	}
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return NewConnection(db, config.Database), nil
+}
+
+// NewConnection : Function returns a Connection served by an opened database, i.e. a MySQL compatible server
+// reached with another driver. Updates report as modified the rows affected, so the driver must count the rows
+// changed rather than the rows found (clientFoundRows=false for the go-sql-driver, its default)
+// Input Parameters
+//		db (*sql.DB) : the database, closed with the Connection
+//		database (string) : database name reported by Connection.Database
+// Output Parameters
+//		*gomongo.Connection : connection whose operations are served by db
+func NewConnection(db *sql.DB, database string) *gomongo.Connection {
+	conn := new(gomongo.Connection)
+	conn.Database = database
+	conn.Store = &Store{db: db, tables: make(map[string]bool)}
+	return conn
+}
+
+// dataSourceName : Function builds the go-sql-driver data source name of config
+func dataSourceName(config *gomongo.Config) (string, error) {
+	var cfg *mysqldriver.Config
+	if len(config.Uri) > 0 {
+		var err error
+		if cfg, err = mysqldriver.ParseDSN(config.Uri); err != nil {
+			return "", fmt.Errorf("%w: %v", gomongo.ErrorInvalidConfig, err)
+		}
+	} else {
+		if len(config.Database) == 0 {
+			return "", fmt.Errorf("%w: the MySQL database is missing, set Database or Uri", gomongo.ErrorInvalidConfig)
+		}
+		cfg = mysqldriver.NewConfig()
+		cfg.Net = "tcp"
+		cfg.Addr = strings.TrimSpace(strings.Split(config.Hosts, ",")[0])
+		if len(config.Addrs) > 0 {
+			cfg.Addr = config.Addrs[0]
+		}
+		cfg.DBName = config.Database
+		cfg.User = config.Username
+		cfg.Passwd = config.Password
+	}
+	if config.DialTimeout > 0 {
+		cfg.Timeout = config.DialTimeout
+	}
+	if config.SocketTimeout > 0 {
+		cfg.ReadTimeout = config.SocketTimeout
+		cfg.WriteTimeout = config.SocketTimeout
+	}
+	//the rows affected by an update are the documents modified, not the ones matched
+	cfg.ClientFoundRows = false
+	return cfg.FormatDSN(), nil
+}
+
+// Store keeps every collection in a table (seq, id, doc) created on first use, seq keeping the insertion order,
+// id being the unique JSON of the _id and doc the JSON document
+type Store struct {
+	db       *sql.DB
+	tablesMu sync.Mutex
+	tables   map[string]bool //tables known to exist
+}
+
+// table : Function returns the quoted table of collection, creating it when missing
+func (s *Store) table(ctx context.Context, collection string) (string, error) {
+	table := "`" + strings.ReplaceAll(collection, "`", "``") + "`"
+
+	s.tablesMu.Lock()
+	defer s.tablesMu.Unlock()
+	if !s.tables[collection] {
+		statement := "CREATE TABLE IF NOT EXISTS " + table + " (seq BIGINT NOT NULL AUTO_INCREMENT PRIMARY KEY, id VARBINARY(255) NOT NULL UNIQUE, doc JSON NOT NULL)"
+		if _, err := s.db.ExecContext(ctx, statement); err != nil {
+			return "", err
+		}
+		s.tables[collection] = true
+	}
+	return table, nil
+}
+
+// Insert : Function inserts docs in a single transaction, none is inserted if one fails
+func (s *Store) Insert(ctx context.Context, collection string, docs []bson.M) error {
+	table, err := s.table(ctx, collection)
+	if err != nil {
+		return err
+	}
+	return s.transaction(ctx, func(tx *sql.Tx) error {
+		for _, doc := range docs {
+			if err := insert(ctx, tx, table, doc); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Find : Function returns the documents matching query in insertion order
+func (s *Store) Find(ctx context.Context, collection string, query bson.M, skip, limit int) ([]bson.M, error) {
+	table, err := s.table(ctx, collection)
+	if err != nil {
+		return nil, err
+	}
+	condition, args, err := where(query)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", gomongo.ErrorValidation, err)
+	}
+
+	statement := "SELECT doc FROM " + table + " WHERE " + condition + " ORDER BY seq"
+	switch {
+	case limit > 0:
+		statement += fmt.Sprintf(" LIMIT %d OFFSET %d", limit, skip)
+	case skip > 0:
+		//MySQL has no OFFSET without LIMIT
+		statement += fmt.Sprintf(" LIMIT 18446744073709551615 OFFSET %d", skip)
+	}
+
+	rows, err := s.db.QueryContext(ctx, statement, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var docs []bson.M
+	for rows.Next() {
+		var data []byte
+		if err := rows.Scan(&data); err != nil {
+			return nil, err
+		}
+		var doc bson.M
+		if err := document.UnmarshalJSON(data, &doc); err != nil {
+			return nil, fmt.Errorf("corrupted document in %s: %v", table, err)
+		}
+		docs = append(docs, doc)
+	}
+	return docs, rows.Err()
+}
+
+// Count : Function returns the number of documents matching query with a single SELECT COUNT(*)
+func (s *Store) Count(ctx context.Context, collection string, query bson.M) (int, error) {
+	table, err := s.table(ctx, collection)
+	if err != nil {
+		return 0, err
+	}
+	condition, args, err := where(query)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %v", gomongo.ErrorValidation, err)
+	}
+	var count int
+	err = s.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM "+table+" WHERE "+condition, args...).Scan(&count)
+	return count, err
+}
+
+// Distinct : Function returns the distinct values at path of the documents matching query with a single SELECT DISTINCT,
+// the union of the ones of every JSON path a dotted path is read at
+func (s *Store) Distinct(ctx context.Context, collection string, path string, query bson.M) ([]interface{}, error) {
+	table, err := s.table(ctx, collection)
+	if err != nil {
+		return nil, err
+	}
+	condition, args, err := where(query)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", gomongo.ErrorValidation, err)
+	}
+	var selects []string
+	var selectArgs []interface{}
+	for _, p := range jsonPaths(path) {
+		selects = append(selects, "SELECT DISTINCT "+extract+" FROM "+table+" WHERE JSON_CONTAINS_PATH(doc, 'one', ?) AND "+condition)
+		selectArgs = append(append(selectArgs, p, p), args...)
+	}
+	rows, err := s.db.QueryContext(ctx, strings.Join(selects, " UNION "), selectArgs...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var docs []bson.M
+	for rows.Next() {
+		var data []byte
+		if err := rows.Scan(&data); err != nil {
+			return nil, err
+		}
+		//the value is decoded in a document, its extended JSON being converted like the one of a document
+		var doc bson.M
+		if err := document.UnmarshalJSON(append(append([]byte(`{"v":`), data...), '}'), &doc); err != nil {
+			return nil, fmt.Errorf("corrupted value of %s in %s: %v", path, table, err)
+		}
+		//the values found in the arrays a dotted path crosses come as an array, the arrays among them are unwound too
+		if list, ok := doc["v"].([]interface{}); ok && strings.Contains(path, ".") {
+			var values []interface{}
+			for _, item := range list {
+				if items, ok := item.([]interface{}); ok {
+					values = append(values, items...)
+				} else {
+					values = append(values, item)
+				}
+			}
+			doc["v"] = values
+		}
+		docs = append(docs, doc)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	//the arrays are unwound and the values equal for MongoDB but not for MySQL (1 and 1.0) merged
+	return document.Distinct(docs, "v"), nil
+}
+
+// Update : Function applies update to the matching documents with a single UPDATE statement,
+// the document inserted by an upsert is built from the query and the update
+func (s *Store) Update(ctx context.Context, collection string, query, update bson.M, multi, upsert bool) (*gomongo.WriteResult, error) {
+	table, err := s.table(ctx, collection)
+	if err != nil {
+		return nil, err
+	}
+	expr, setArgs, err := set(update)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", gomongo.ErrorValidation, err)
+	}
+	condition, whereArgs, err := where(query)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", gomongo.ErrorValidation, err)
+	}
+	statement := "UPDATE " + table + " SET doc = " + expr + " WHERE " + condition
+	if !multi {
+		statement += " ORDER BY seq LIMIT 1"
+	}
+
+	info := new(gomongo.WriteResult)
+	err = s.transaction(ctx, func(tx *sql.Tx) error {
+		//the rows matched are counted and locked first, the UPDATE only reports the rows it changed
+		var matched int
+		if err := tx.QueryRowContext(ctx, "SELECT COUNT(*) FROM "+table+" WHERE "+condition+" FOR UPDATE", whereArgs...).Scan(&matched); err != nil {
+			return err
+		}
+		if !multi && matched > 1 {
+			matched = 1
+		}
+		info.Matched = matched
+		if matched > 0 {
+			result, err := tx.ExecContext(ctx, statement, append(setArgs, whereArgs...)...)
+			if err != nil {
+				return err
+			}
+			modified, err := result.RowsAffected()
+			if err != nil {
+				return err
+			}
+			info.Modified = int(modified)
+			return nil
+		}
+		if !upsert {
+			return nil
+		}
+
+		doc, err := document.Upserted(query, update)
+		if err != nil {
+			return fmt.Errorf("%w: %v", gomongo.ErrorValidation, err)
+		}
+		if _, ok := doc["_id"]; !ok {
+			doc["_id"] = bson.NewObjectId()
+		}
+		info.UpsertedId = doc["_id"]
+		return insert(ctx, tx, table, doc)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return info, nil
+}
+
+// Remove : Function deletes the matching documents with a single DELETE statement
+func (s *Store) Remove(ctx context.Context, collection string, query bson.M, multi bool) (*gomongo.WriteResult, error) {
+	table, err := s.table(ctx, collection)
+	if err != nil {
+		return nil, err
+	}
+	condition, args, err := where(query)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", gomongo.ErrorValidation, err)
+	}
+	statement := "DELETE FROM " + table + " WHERE " + condition
+	if !multi {
+		statement += " ORDER BY seq LIMIT 1"
+	}
+
+	result, err := s.db.ExecContext(ctx, statement, args...)
+	if err != nil {
+		return nil, err
+	}
+	removed, err := result.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+	return &gomongo.WriteResult{Matched: int(removed), Removed: int(removed)}, nil
+}
+
+// FindAndModify : Function changes or removes the first document matching query in the order of sort, in a transaction
+// which locks the matching rows from the SELECT to the change
+func (s *Store) FindAndModify(ctx context.Context, collection string, query bson.M, sort bson.D, update bson.M, upsert bool) (bson.M, bson.M, error) {
+	table, err := s.table(ctx, collection)
+	if err != nil {
+		return nil, nil, err
+	}
+	var keys []document.SortKey
+	if len(sort) > 0 {
+		if keys, err = document.ParseSort(sort); err != nil {
+			return nil, nil, fmt.Errorf("%w: %v", gomongo.ErrorValidation, err)
+		}
+	}
+	condition, args, err := where(query)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", gomongo.ErrorValidation, err)
+	}
+
+	var before, after bson.M
+	err = s.transaction(ctx, func(tx *sql.Tx) error {
+		rows, err := tx.QueryContext(ctx, "SELECT seq, doc FROM "+table+" WHERE "+condition+" ORDER BY seq FOR UPDATE", args...)
+		if err != nil {
+			return err
+		}
+		var seqs []int64
+		var docs []bson.M
+		for rows.Next() {
+			var seq int64
+			var data []byte
+			if err := rows.Scan(&seq, &data); err != nil {
+				rows.Close()
+				return err
+			}
+			var doc bson.M
+			if err := document.UnmarshalJSON(data, &doc); err != nil {
+				rows.Close()
+				return fmt.Errorf("corrupted document in %s: %v", table, err)
+			}
+			seqs = append(seqs, seq)
+			docs = append(docs, doc)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return err
+		}
+
+		if len(docs) == 0 {
+			if update == nil || !upsert {
+				return nil
+			}
+			doc, err := document.Upserted(query, update)
+			if err != nil {
+				return fmt.Errorf("%w: %v", gomongo.ErrorValidation, err)
+			}
+			if _, ok := doc["_id"]; !ok {
+				doc["_id"] = bson.NewObjectId()
+			}
+			after = doc
+			return insert(ctx, tx, table, doc)
+		}
+
+		first := document.First(docs, keys)
+		before = docs[first]
+		if update == nil {
+			_, err := tx.ExecContext(ctx, "DELETE FROM "+table+" WHERE seq = ?", seqs[first])
+			return err
+		}
+		if after, err = document.Apply(before, update, false); err != nil {
+			return fmt.Errorf("%w: %v", gomongo.ErrorValidation, err)
+		}
+		data, err := jsonValue(after)
+		if err != nil {
+			return err
+		}
+		_, err = tx.ExecContext(ctx, "UPDATE "+table+" SET doc = CAST(? AS JSON) WHERE seq = ?", data, seqs[first])
+		return err
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return before, after, nil
+}
+
+// Ping : Function checks the database can be reached
+func (s *Store) Ping(ctx context.Context) error {
+	return s.db.PingContext(ctx)
+}
+
+// Close : Function closes the database
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// transaction : Function runs op in a transaction, committed when op succeeds
+func (s *Store) transaction(ctx context.Context, op func(tx *sql.Tx) error) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	if err := op(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// insert : Function inserts a single document
+func insert(ctx context.Context, tx *sql.Tx, table string, doc bson.M) error {
+	key, err := idKey(doc["_id"])
+	if err != nil {
+		return err
+	}
+	data, err := jsonValue(doc)
+	if err != nil {
+		return err
+	}
+	_, err = tx.ExecContext(ctx, "INSERT INTO "+table+" (id, doc) VALUES (?, CAST(? AS JSON))", key, data)
+	var mysqlErr *mysqldriver.MySQLError
+	if errors.As(err, &mysqlErr) && mysqlErr.Number == errDuplicateEntry {
+		return fmt.Errorf("%w: _id %s already exists in %s", gomongo.ErrorDuplicateKey, key, table)
+	}
+	return err
+}
@@ -0,0 +1,402 @@
+package mysql
+
+import (
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/globalsign/mgo/bson"
+	mysqldriver "github.com/go-sql-driver/mysql"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/alishavirani/gomongo"
+	"github.com/alishavirani/gomongo/internal/document"
+)
+
+const createUsers = "CREATE TABLE IF NOT EXISTS `users` (seq BIGINT NOT NULL AUTO_INCREMENT PRIMARY KEY, id VARBINARY(255) NOT NULL UNIQUE, doc JSON NOT NULL)"
+
+func TestWhere(t *testing.T) {
+	condition, args, err := where(nil)
+	assert.Nil(t, err)
+	assert.Equal(t, "TRUE", condition)
+	assert.Empty(t, args)
+
+	condition, args, err = where(bson.M{"_id": 7})
+	assert.Nil(t, err)
+	assert.Equal(t, "id = ?", condition)
+	assert.Equal(t, []interface{}{"7"}, args)
+
+	//a dotted path is also read in the documents of the arrays it crosses
+	condition, args, err = where(bson.M{"address.city": "Pune"})
+	assert.Nil(t, err)
+	assert.Equal(t, "COALESCE(JSON_EXTRACT(doc, ?) = CAST(? AS JSON) OR (JSON_TYPE(JSON_EXTRACT(doc, ?)) = 'ARRAY' AND CAST(? AS JSON) MEMBER OF(JSON_EXTRACT(doc, ?))) OR CAST(? AS JSON) MEMBER OF(JSON_EXTRACT(doc, ?)) OR CAST(? AS JSON) MEMBER OF(JSON_EXTRACT(doc, ?)), FALSE)", condition)
+	assert.Equal(t, []interface{}{`$."address"."city"`, `"Pune"`, `$."address"."city"`, `"Pune"`, `$."address"."city"`, `"Pune"`, `$."address"[*]."city"`, `"Pune"`, `$."address"[*]."city"[*]`}, args)
+
+	condition, args, err = where(bson.M{"items.0.price": bson.M{"$gt": 2, "$exists": true}})
+	assert.Nil(t, err)
+	assert.Equal(t, "(JSON_CONTAINS_PATH(doc, 'one', ?, ?, ?, ?, ?, ?) AND COALESCE(JSON_TYPE(JSON_EXTRACT(doc, ?)) IN "+numberTypes+" AND JSON_EXTRACT(doc, ?) > CAST(? AS JSON) OR JSON_TYPE(JSON_EXTRACT(doc, ?)) IN "+numberTypes+" AND JSON_EXTRACT(doc, ?) > CAST(? AS JSON), FALSE))", condition)
+	assert.Equal(t, []interface{}{`$."items"."0"."price"`, `$."items"[*]."0"."price"`, `$."items"[0]."price"`, `$."items"."0"[*]."price"`, `$."items"[*]."0"[*]."price"`, `$."items"[0][*]."price"`,
+		`$."items"."0"."price"`, `$."items"."0"."price"`, "2", `$."items"[0]."price"`, `$."items"[0]."price"`, "2"}, args[:12])
+
+	condition, args, err = where(bson.M{"age": bson.M{"$gte": 18, "$exists": true}})
+	assert.Nil(t, err)
+	assert.Equal(t, "(JSON_CONTAINS_PATH(doc, 'one', ?) AND COALESCE(JSON_TYPE(JSON_EXTRACT(doc, ?)) IN "+numberTypes+" AND JSON_EXTRACT(doc, ?) >= CAST(? AS JSON), FALSE))", condition)
+	assert.Equal(t, []interface{}{`$."age"`, `$."age"`, `$."age"`, "18"}, args)
+
+	joined := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	_, args, err = where(bson.M{"joined": bson.M{"$lt": joined}})
+	assert.Nil(t, err)
+	assert.Equal(t, []interface{}{`$."joined"."$date"`, `$."joined"."$date"`, `"2020-01-02T03:04:05.000Z"`}, args)
+
+	condition, _, err = where(bson.M{"$or": []interface{}{bson.M{"name": nil}, bson.M{"tags": bson.M{"$nin": []interface{}{}}}}})
+	assert.Nil(t, err)
+	assert.Equal(t, "((COALESCE(JSON_EXTRACT(doc, ?) IS NULL OR JSON_TYPE(JSON_EXTRACT(doc, ?)) = 'NULL', TRUE)) OR ((NOT FALSE)))", condition)
+
+	for _, query := range []bson.M{
+		{"name": bson.M{"$regex": "^A"}},
+		{"$where": "this.age > 1"},
+		{"age": bson.M{"$gt": []interface{}{1}}},
+	} {
+		_, _, err = where(query)
+		assert.True(t, errors.Is(err, document.ErrUnsupported), "%v", query)
+	}
+}
+
+func TestSet(t *testing.T) {
+	expr, args, err := set(bson.M{"name": "Ravi", "_id": 9})
+	assert.Nil(t, err)
+	assert.Equal(t, "JSON_SET(CAST(? AS JSON), '$._id', JSON_EXTRACT(doc, '$._id'))", expr)
+	assert.Equal(t, []interface{}{`{"name":"Ravi"}`}, args)
+
+	expr, args, err = set(bson.M{
+		"$set":         bson.M{"address.city": "Pune"},
+		"$inc":         bson.M{"age": 1},
+		"$unset":       bson.M{"nickname": ""},
+		"$setOnInsert": bson.M{"created": true},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, "JSON_REMOVE(JSON_SET(JSON_SET(JSON_SET(doc, ?, COALESCE(JSON_EXTRACT(doc, ?), JSON_OBJECT())), ?, COALESCE(JSON_EXTRACT(doc, ?), 0) + ?), ?, CAST(? AS JSON)), ?)", expr)
+	assert.Equal(t, []interface{}{`$."address"`, `$."address"`, `$."age"`, `$."age"`, int64(1), `$."address"."city"`, `"Pune"`, `$."nickname"`}, args)
+
+	//only the increments which aren't integers are bound as floats
+	_, args, err = set(bson.M{"$inc": bson.M{"score": 0.5}})
+	assert.Nil(t, err)
+	assert.Equal(t, []interface{}{`$."score"`, `$."score"`, 0.5}, args)
+
+	expr, args, err = set(bson.M{"$push": bson.M{"tags": bson.M{"$each": []interface{}{"ops", "dev"}}}})
+	assert.Nil(t, err)
+	assert.Equal(t, "JSON_SET(doc, ?, IF(JSON_TYPE(JSON_EXTRACT(doc, ?)) = 'ARRAY', JSON_MERGE_PRESERVE(JSON_EXTRACT(doc, ?), CAST(? AS JSON)), CAST(? AS JSON)))", expr)
+	assert.Equal(t, []interface{}{`$."tags"`, `$."tags"`, `$."tags"`, `["ops","dev"]`, `["ops","dev"]`}, args)
+
+	_, _, err = set(bson.M{"$set": bson.M{"_id": 2}})
+	assert.True(t, errors.Is(err, document.ErrImmutableID))
+
+	_, _, err = set(bson.M{"$rename": bson.M{"name": "firstName"}})
+	assert.True(t, errors.Is(err, document.ErrUnsupported))
+}
+
+func TestDateRoundTrip(t *testing.T) {
+	joined := time.Date(2020, 1, 2, 3, 4, 5, 6000000, time.UTC)
+	data, err := jsonValue(bson.M{"joined": joined})
+	assert.Nil(t, err)
+
+	var doc bson.M
+	assert.Nil(t, bson.UnmarshalJSON([]byte(data), &doc))
+	assert.True(t, joined.Equal(doc["joined"].(time.Time)))
+}
+
+func TestDataSourceName(t *testing.T) {
+	dsn, err := dataSourceName(&gomongo.Config{DbType: gomongo.MYSQL, Hosts: "db1:3306,db2:3306", Database: "app", Username: "app", Password: "secret", DialTimeout: time.Second})
+	assert.Nil(t, err)
+	assert.Equal(t, "app:secret@tcp(db1:3306)/app?timeout=1s", dsn)
+
+	//a Uri counting the rows found is overridden, the rows affected are the documents modified
+	dsn, err = dataSourceName(&gomongo.Config{DbType: gomongo.MYSQL, Uri: "app@unix(/tmp/mysql.sock)/app?clientFoundRows=true"})
+	assert.Nil(t, err)
+	assert.Equal(t, "app@unix(/tmp/mysql.sock)/app", dsn)
+
+	_, err = dataSourceName(&gomongo.Config{DbType: gomongo.MYSQL, Hosts: "db1:3306"})
+	assert.True(t, errors.Is(err, gomongo.ErrorInvalidConfig))
+}
+
+func mockConnection(t *testing.T) (*gomongo.Connection, sqlmock.Sqlmock) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+	t.Cleanup(func() {
+		db.Close()
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+	mock.ExpectExec(createUsers).WillReturnResult(sqlmock.NewResult(0, 0))
+	return NewConnection(db, "app"), mock
+}
+
+func TestInsertDuplicateKey(t *testing.T) {
+	conn, mock := mockConnection(t)
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO `users` (id, doc) VALUES (?, CAST(? AS JSON))").
+		WithArgs("7", `{"_id":7,"firstName":"Ravi"}`).
+		WillReturnError(&mysqldriver.MySQLError{Number: errDuplicateEntry, Message: "Duplicate entry"})
+	mock.ExpectRollback()
+
+	_, err := conn.C("users").Insert(&gomongo.InsertStruct{Data: bson.M{"_id": 7, "firstName": "Ravi"}})
+	assert.True(t, errors.Is(err, gomongo.ErrorDuplicateKey))
+}
+
+func TestFindByID(t *testing.T) {
+	conn, mock := mockConnection(t)
+	mock.ExpectQuery("SELECT doc FROM `users` WHERE id = ? ORDER BY seq LIMIT 1 OFFSET 0").
+		WithArgs("7").
+		WillReturnRows(sqlmock.NewRows([]string{"doc"}).AddRow(`{"_id": 7, "firstName": "Ravi", "age": 40.0, "score": 2.5, "joined": {"$date": "2020-01-02T03:04:05.000Z"}}`))
+	mock.ExpectQuery("SELECT doc FROM `users` WHERE id = ? ORDER BY seq LIMIT 1 OFFSET 0").
+		WithArgs("8").
+		WillReturnRows(sqlmock.NewRows([]string{"doc"}))
+
+	users := conn.C("users")
+	record, err := users.FindByID(&gomongo.FindByIDStruct{Id: 7})
+	assert.Nil(t, err)
+	assert.Equal(t, "Ravi", record.(bson.M)["firstName"])
+	//JSON numbers without a fractional part are read back as integers
+	assert.Equal(t, 7, record.(bson.M)["_id"])
+	assert.Equal(t, 40, record.(bson.M)["age"])
+	assert.Equal(t, 2.5, record.(bson.M)["score"])
+	assert.Equal(t, time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC), record.(bson.M)["joined"].(time.Time).UTC())
+
+	_, err = users.FindByID(&gomongo.FindByIDStruct{Id: 8})
+	assert.True(t, errors.Is(err, gomongo.ErrorNotFound))
+}
+
+func TestUpsertInsertsWhenNothingMatches(t *testing.T) {
+	conn, mock := mockConnection(t)
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT COUNT(*) FROM `users` WHERE id = ? FOR UPDATE").
+		WithArgs(`"anu"`).
+		WillReturnRows(sqlmock.NewRows([]string{"COUNT(*)"}).AddRow(0))
+	mock.ExpectExec("INSERT INTO `users` (id, doc) VALUES (?, CAST(? AS JSON))").
+		WithArgs(`"anu"`, `{"_id":"anu","age":22}`).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	info, err := conn.C("users").Upsert(&gomongo.UpsertStruct{Id: gomongo.StringID("anu"), Data: bson.M{"$set": bson.M{"age": 22}}})
+	assert.Nil(t, err)
+	assert.Equal(t, "anu", info.UpsertedId)
+}
+
+func TestUpdateMatchedModified(t *testing.T) {
+	//without clientFoundRows the rows affected are the rows changed, a no-op update matches without modifying
+	conn, mock := mockConnection(t)
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT COUNT(*) FROM `users` WHERE id = ? FOR UPDATE").
+		WithArgs("7").
+		WillReturnRows(sqlmock.NewRows([]string{"COUNT(*)"}).AddRow(1))
+	mock.ExpectExec("UPDATE `users` SET doc = JSON_SET(doc, ?, CAST(? AS JSON)) WHERE id = ? ORDER BY seq LIMIT 1").
+		WithArgs(`$."age"`, "40", "7").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	users := conn.C("users")
+	info, err := users.Update(&gomongo.UpdateStruct{Id: 7, Data: bson.M{"$set": bson.M{"age": 40}}})
+	assert.Nil(t, err)
+	assert.Equal(t, &gomongo.WriteResult{Matched: 1, Modified: 0}, info)
+
+	//a single update matches one row however many the query selects
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT COUNT(*) FROM `users` WHERE TRUE FOR UPDATE").
+		WillReturnRows(sqlmock.NewRows([]string{"COUNT(*)"}).AddRow(3))
+	mock.ExpectExec("UPDATE `users` SET doc = JSON_SET(doc, ?, CAST(? AS JSON)) WHERE TRUE ORDER BY seq LIMIT 1").
+		WithArgs(`$."age"`, "40").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	info, err = users.UpdateOne(gomongo.UpdateOneStruct{Query: bson.M{}, Data: bson.M{"$set": bson.M{"age": 40}}})
+	assert.Nil(t, err)
+	assert.Equal(t, &gomongo.WriteResult{Matched: 1, Modified: 1}, info)
+
+	//some of the matched rows already had the values
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT COUNT(*) FROM `users` WHERE TRUE FOR UPDATE").
+		WillReturnRows(sqlmock.NewRows([]string{"COUNT(*)"}).AddRow(3))
+	mock.ExpectExec("UPDATE `users` SET doc = JSON_SET(doc, ?, CAST(? AS JSON)) WHERE TRUE").
+		WithArgs(`$."age"`, "40").
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectCommit()
+
+	info, err = users.UpdateAll(gomongo.UpdateAllStruct{Query: bson.M{}, Data: bson.M{"$set": bson.M{"age": 40}}})
+	assert.Nil(t, err)
+	assert.Equal(t, &gomongo.WriteResult{Matched: 3, Modified: 2}, info)
+
+	//no row matched, the UPDATE isn't run and the update is not found
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT COUNT(*) FROM `users` WHERE id = ? FOR UPDATE").
+		WithArgs("8").
+		WillReturnRows(sqlmock.NewRows([]string{"COUNT(*)"}).AddRow(0))
+	mock.ExpectCommit()
+
+	_, err = users.Update(&gomongo.UpdateStruct{Id: 8, Data: bson.M{"$set": bson.M{"age": 40}}})
+	assert.True(t, errors.Is(err, gomongo.ErrorNotFound))
+}
+
+func TestCountDistinct(t *testing.T) {
+	conn, mock := mockConnection(t)
+	mock.ExpectQuery("SELECT COUNT(*) FROM `users` WHERE (COALESCE(JSON_TYPE(JSON_EXTRACT(doc, ?)) IN " + numberTypes + " AND JSON_EXTRACT(doc, ?) < CAST(? AS JSON), FALSE))").
+		WithArgs(`$."age"`, `$."age"`, "30").
+		WillReturnRows(sqlmock.NewRows([]string{"COUNT(*)"}).AddRow(2))
+	mock.ExpectQuery("SELECT DISTINCT JSON_EXTRACT(doc, ?) FROM `users` WHERE JSON_CONTAINS_PATH(doc, 'one', ?) AND TRUE").
+		WithArgs(`$."tags"`, `$."tags"`).
+		WillReturnRows(sqlmock.NewRows([]string{"tags"}).AddRow(`["admin", "ops"]`).AddRow(`["ops"]`).AddRow(`"dev"`))
+	mock.ExpectQuery("SELECT DISTINCT JSON_EXTRACT(doc, ?) FROM `users` WHERE JSON_CONTAINS_PATH(doc, 'one', ?) AND TRUE UNION SELECT DISTINCT JSON_EXTRACT(doc, ?) FROM `users` WHERE JSON_CONTAINS_PATH(doc, 'one', ?) AND TRUE").
+		WithArgs(`$."address"."city"`, `$."address"."city"`, `$."address"[*]."city"`, `$."address"[*]."city"`).
+		WillReturnRows(sqlmock.NewRows([]string{"city"}).AddRow(`"Pune"`).AddRow(`["Goa", ["Agra", "Pune"]]`))
+
+	users := conn.C("users")
+	count, err := users.Count(&gomongo.CountStruct{Query: bson.M{"age": bson.M{"$lt": 30}}})
+	assert.Nil(t, err)
+	assert.Equal(t, 2, count)
+
+	//the arrays selected are unwound and their elements merged
+	tags, err := users.Distinct(&gomongo.DistinctStruct{Field: "tags"})
+	assert.Nil(t, err)
+	assert.Equal(t, []interface{}{"admin", "ops", "dev"}, tags)
+
+	//a dotted path is read in the documents of the arrays it crosses too
+	cities, err := users.Distinct(&gomongo.DistinctStruct{Field: "address.city"})
+	assert.Nil(t, err)
+	assert.Equal(t, []interface{}{"Pune", "Goa", "Agra"}, cities)
+}
+
+func TestFindOneAndModify(t *testing.T) {
+	//the matching rows are locked until the one sorted first is changed
+	conn, mock := mockConnection(t)
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT seq, doc FROM `users` WHERE TRUE ORDER BY seq FOR UPDATE").
+		WillReturnRows(sqlmock.NewRows([]string{"seq", "doc"}).AddRow(1, `{"_id": 7, "age": 40}`).AddRow(2, `{"_id": 8, "age": 26}`))
+	mock.ExpectExec("UPDATE `users` SET doc = CAST(? AS JSON) WHERE seq = ?").
+		WithArgs(`{"_id":8,"age":27}`, 2).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT seq, doc FROM `users` WHERE id = ? ORDER BY seq FOR UPDATE").
+		WithArgs("7").
+		WillReturnRows(sqlmock.NewRows([]string{"seq", "doc"}).AddRow(1, `{"_id": 7, "age": 40}`))
+	mock.ExpectExec("DELETE FROM `users` WHERE seq = ?").
+		WithArgs(1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT seq, doc FROM `users` WHERE id = ? ORDER BY seq FOR UPDATE").
+		WithArgs("7").
+		WillReturnRows(sqlmock.NewRows([]string{"seq", "doc"}))
+	mock.ExpectCommit()
+
+	users := conn.C("users")
+	after, err := users.FindOneAndUpdate(&gomongo.FindOneAndUpdateStruct{Query: bson.M{}, Data: bson.M{"$inc": bson.M{"age": 1}}, Sort: []string{"age"}, ReturnNew: true})
+	assert.Nil(t, err)
+	assert.Equal(t, bson.M{"_id": 8, "age": 27}, after)
+
+	removed, err := users.FindOneAndDelete(&gomongo.FindOneAndDeleteStruct{Query: bson.M{"_id": 7}})
+	assert.Nil(t, err)
+	assert.Equal(t, bson.M{"_id": 7, "age": 40}, removed)
+
+	_, err = users.FindOneAndDelete(&gomongo.FindOneAndDeleteStruct{Query: bson.M{"_id": 7}})
+	assert.True(t, errors.Is(err, gomongo.ErrorNotFound))
+}
+
+func TestRemove(t *testing.T) {
+	conn, mock := mockConnection(t)
+	mock.ExpectExec("DELETE FROM `users` WHERE (COALESCE(JSON_TYPE(JSON_EXTRACT(doc, ?)) IN " + numberTypes + " AND JSON_EXTRACT(doc, ?) < CAST(? AS JSON), FALSE)) ORDER BY seq LIMIT 1").
+		WithArgs(`$."age"`, `$."age"`, "30").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("DELETE FROM `users` WHERE TRUE").
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	users := conn.C("users")
+	_, err := users.Remove(&gomongo.RemoveStruct{Query: bson.M{"age": bson.M{"$lt": 30}}})
+	assert.True(t, errors.Is(err, gomongo.ErrorNotFound))
+
+	info, err := users.RemoveAll(&gomongo.RemoveAllStruct{})
+	assert.Nil(t, err)
+	assert.Equal(t, 2, info.Removed)
+}
+
+// TestServer runs against the MySQL compatible server of GOMONGO_MYSQL_DSN, i.e. "root:secret@tcp(localhost:3306)/test",
+// and is skipped when it isn't set. A CI job starts a mysql:8.0 container and exports the variable, see the MySQL section of the README
+func TestServer(t *testing.T) {
+	dsn := os.Getenv("GOMONGO_MYSQL_DSN")
+	if len(dsn) == 0 {
+		t.Skip("GOMONGO_MYSQL_DSN is not set")
+	}
+	db, err := gomongo.Init(gomongo.MYSQL)
+	assert.Nil(t, err)
+	conn, err := db.Connect(&gomongo.Config{DbType: gomongo.MYSQL, Uri: dsn})
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+	defer gomongo.Close(conn)
+
+	collection := "gomongo_test_" + bson.NewObjectId().Hex()
+	defer conn.Store.(*Store).db.Exec("DROP TABLE `" + collection + "`")
+	users := conn.C(collection)
+
+	_, err = users.BulkInsert(&gomongo.BulkInsertStruct{Data: []interface{}{
+		bson.M{"firstName": "Amulya", "age": 26, "tags": []string{"admin"}},
+		bson.M{"firstName": "Kasyap", "age": 31},
+		bson.M{"_id": 7, "firstName": "Ravi", "age": 40},
+	}})
+	assert.Nil(t, err)
+	_, err = users.Insert(&gomongo.InsertStruct{Data: bson.M{"_id": 7}})
+	assert.True(t, errors.Is(err, gomongo.ErrorDuplicateKey))
+
+	records, err := users.Find(&gomongo.FindStruct{Query: bson.M{"age": bson.M{"$gt": 30}}})
+	assert.Nil(t, err)
+	assert.Len(t, records, 2)
+
+	records, err = users.Find(&gomongo.FindStruct{Query: bson.M{"tags": "admin"}})
+	assert.Nil(t, err)
+	assert.Len(t, records, 1)
+
+	_, err = users.Insert(&gomongo.InsertStruct{Data: bson.M{"_id": 9, "orders": []interface{}{bson.M{"item": "pen", "tags": []string{"gift"}}, bson.M{"item": "ink"}}}})
+	assert.Nil(t, err)
+	for _, query := range []bson.M{{"orders.item": "ink"}, {"orders.tags": "gift"}, {"orders.1.item": "ink"}, {"orders.item": bson.M{"$exists": true}}} {
+		records, err = users.Find(&gomongo.FindStruct{Query: query})
+		assert.Nil(t, err)
+		assert.Len(t, records, 1, "%v", query)
+	}
+	items, err := users.Distinct(&gomongo.DistinctStruct{Field: "orders.item"})
+	assert.Nil(t, err)
+	assert.ElementsMatch(t, []interface{}{"pen", "ink"}, items)
+	_, err = users.Remove(&gomongo.RemoveStruct{Query: bson.M{"_id": 9}})
+	assert.Nil(t, err)
+
+	info, err := users.UpdateAll(gomongo.UpdateAllStruct{Query: bson.M{"age": bson.M{"$lt": 35}}, Data: bson.M{"$inc": bson.M{"age": 1}, "$set": bson.M{"address.city": "Pune"}}})
+	assert.Nil(t, err)
+	assert.Equal(t, 2, info.Matched)
+	assert.Equal(t, 2, info.Modified)
+
+	//setting the values a document already has matches it without modifying it
+	info, err = users.UpdateAll(gomongo.UpdateAllStruct{Query: bson.M{"_id": 7}, Data: bson.M{"$set": bson.M{"age": 40}}})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, info.Matched)
+	assert.Equal(t, 0, info.Modified)
+
+	records, err = users.Find(&gomongo.FindStruct{Query: bson.M{"address.city": "Pune", "age": 27}})
+	assert.Nil(t, err)
+	assert.Len(t, records, 1)
+
+	count, err := users.Count(&gomongo.CountStruct{Query: bson.M{"address.city": "Pune"}})
+	assert.Nil(t, err)
+	assert.Equal(t, 2, count)
+	tags, err := users.Distinct(&gomongo.DistinctStruct{Field: "tags"})
+	assert.Nil(t, err)
+	assert.Equal(t, []interface{}{"admin"}, tags)
+
+	info, err = users.Upsert(&gomongo.UpsertStruct{Id: gomongo.StringID("anu"), Data: bson.M{"$set": bson.M{"firstName": "Anu"}}})
+	assert.Nil(t, err)
+	assert.Equal(t, "anu", info.UpsertedId)
+
+	info, err = users.RemoveAll(&gomongo.RemoveAllStruct{})
+	assert.Nil(t, err)
+	assert.Equal(t, 4, info.Removed)
+}
@@ -0,0 +1,469 @@
+package mysql
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/globalsign/mgo/bson"
+
+	"github.com/alishavirani/gomongo"
+	"github.com/alishavirani/gomongo/internal/document"
+)
+
+// dateFormat is the fixed width form of the dates stored in the documents, so they compare as strings
+const dateFormat = "2006-01-02T15:04:05.000Z"
+
+// extract is the value at the JSON path given as argument
+const extract = "JSON_EXTRACT(doc, ?)"
+
+// the JSON_TYPE values a field must have to be compared with a value of the same Go kind
+var (
+	numberTypes = "('INTEGER', 'UNSIGNED INTEGER', 'DOUBLE', 'DECIMAL')"
+	stringTypes = "('STRING')"
+	boolTypes   = "('BOOLEAN')"
+)
+
+// where : Function translates a query into a SQL condition on the doc column and its arguments
+// Supported : implicit equality, $eq, $ne, $gt, $gte, $lt, $lte, $in, $nin, $exists, $not, $and, $or and $nor.
+// Equality also matches the elements of an array field, and equality and $exists go into the documents
+// of the arrays a dotted path crosses, like MongoDB does. $gt, $gte, $lt and $lte only read single values
+func where(query bson.M) (string, []interface{}, error) {
+	if len(query) == 0 {
+		return "TRUE", nil, nil
+	}
+
+	var conditions []string
+	var args []interface{}
+	for _, key := range sortedKeys(query) {
+		var condition string
+		var conditionArgs []interface{}
+		var err error
+		switch key {
+		case "$and", "$or", "$nor":
+			condition, conditionArgs, err = logical(key, query[key])
+		case "_id":
+			condition, conditionArgs, err = idCondition(query[key])
+		default:
+			if strings.HasPrefix(key, "$") {
+				return "", nil, fmt.Errorf("%w %s", document.ErrUnsupported, key)
+			}
+			condition, conditionArgs, err = field(key, query[key])
+		}
+		if err != nil {
+			return "", nil, err
+		}
+		conditions = append(conditions, condition)
+		args = append(args, conditionArgs...)
+	}
+	return strings.Join(conditions, " AND "), args, nil
+}
+
+// logical : Function translates $and, $or and $nor
+func logical(op string, value interface{}) (string, []interface{}, error) {
+	clauses, ok := value.([]interface{})
+	if !ok || len(clauses) == 0 {
+		return "", nil, fmt.Errorf("%s needs a non empty array", op)
+	}
+
+	var conditions []string
+	var args []interface{}
+	for _, clause := range clauses {
+		query, ok := clause.(bson.M)
+		if !ok {
+			return "", nil, fmt.Errorf("%s needs an array of documents", op)
+		}
+		condition, conditionArgs, err := where(query)
+		if err != nil {
+			return "", nil, err
+		}
+		conditions = append(conditions, "("+condition+")")
+		args = append(args, conditionArgs...)
+	}
+
+	switch op {
+	case "$and":
+		return "(" + strings.Join(conditions, " AND ") + ")", args, nil
+	case "$or":
+		return "(" + strings.Join(conditions, " OR ") + ")", args, nil
+	default:
+		return "NOT (" + strings.Join(conditions, " OR ") + ")", args, nil
+	}
+}
+
+// idCondition : Function looks a plain _id up by the id column, other conditions on _id go through the document
+func idCondition(value interface{}) (string, []interface{}, error) {
+	if _, ok := document.Operators(value); ok || value == nil {
+		return field("_id", value)
+	}
+	key, err := idKey(value)
+	if err != nil {
+		return "", nil, err
+	}
+	return "id = ?", []interface{}{key}, nil
+}
+
+// field : Function translates the condition on a single field
+func field(path string, condition interface{}) (string, []interface{}, error) {
+	operators, ok := document.Operators(condition)
+	if !ok {
+		return equals(path, condition)
+	}
+
+	var conditions []string
+	var args []interface{}
+	for _, op := range sortedKeys(operators) {
+		operand := operators[op]
+		var condition string
+		var conditionArgs []interface{}
+		var err error
+		switch op {
+		case "$eq":
+			condition, conditionArgs, err = equals(path, operand)
+		case "$ne":
+			condition, conditionArgs, err = equals(path, operand)
+			condition = "NOT " + condition
+		case "$gt", "$gte", "$lt", "$lte":
+			condition, conditionArgs, err = compare(path, op, operand)
+		case "$in", "$nin":
+			condition, conditionArgs, err = in(path, operand)
+			if op == "$nin" {
+				condition = "NOT " + condition
+			}
+		case "$exists":
+			paths := jsonPaths(path)
+			condition = "JSON_CONTAINS_PATH(doc, 'one'" + strings.Repeat(", ?", len(paths)) + ")"
+			conditionArgs = nil
+			for _, p := range paths {
+				conditionArgs = append(conditionArgs, p)
+			}
+			exists, _ := operand.(bool)
+			if n, ok := document.Number(operand); ok {
+				exists = n != 0
+			}
+			if !exists {
+				condition = "NOT " + condition
+			}
+		case "$not":
+			condition, conditionArgs, err = field(path, operand)
+			condition = "NOT " + condition
+		default:
+			err = fmt.Errorf("%w %s", document.ErrUnsupported, op)
+		}
+		if err != nil {
+			return "", nil, err
+		}
+		conditions = append(conditions, condition)
+		args = append(args, conditionArgs...)
+	}
+	return "(" + strings.Join(conditions, " AND ") + ")", args, nil
+}
+
+// equals : Function matches a field equal to value, or an array field holding value
+// A missing field equals nil, and every condition is made TRUE or FALSE so NOT works on missing fields
+func equals(path string, value interface{}) (string, []interface{}, error) {
+	p := jsonPath(path)
+	if value == nil {
+		return "COALESCE(" + extract + " IS NULL OR JSON_TYPE(" + extract + ") = 'NULL', TRUE)", []interface{}{p, p}, nil
+	}
+	j, err := jsonValue(value)
+	if err != nil {
+		return "", nil, err
+	}
+	var conditions []string
+	var args []interface{}
+	for _, p := range jsonPaths(path) {
+		if isWildcard(p) {
+			//the values found in the elements of the arrays, and the elements of the arrays found
+			conditions = append(conditions, "CAST(? AS JSON) MEMBER OF("+extract+")", "CAST(? AS JSON) MEMBER OF("+extract+")")
+			args = append(args, j, p, j, p+"[*]")
+			continue
+		}
+		conditions = append(conditions, extract+" = CAST(? AS JSON)", "(JSON_TYPE("+extract+") = 'ARRAY' AND CAST(? AS JSON) MEMBER OF("+extract+"))")
+		args = append(args, p, j, p, j, p)
+	}
+	return "COALESCE(" + strings.Join(conditions, " OR ") + ", FALSE)", args, nil
+}
+
+// in : Function matches a field equal to one of the values of list
+func in(path string, list interface{}) (string, []interface{}, error) {
+	values, ok := list.([]interface{})
+	if !ok {
+		return "", nil, fmt.Errorf("$in and $nin need an array")
+	}
+	if len(values) == 0 {
+		return "FALSE", nil, nil
+	}
+	var conditions []string
+	var args []interface{}
+	for _, value := range values {
+		condition, conditionArgs, err := equals(path, value)
+		if err != nil {
+			return "", nil, err
+		}
+		conditions = append(conditions, condition)
+		args = append(args, conditionArgs...)
+	}
+	return "(" + strings.Join(conditions, " OR ") + ")", args, nil
+}
+
+// compare : Function translates $gt, $gte, $lt and $lte, only values of the same kind are compared
+func compare(path, op string, value interface{}) (string, []interface{}, error) {
+	operator := map[string]string{"$gt": ">", "$gte": ">=", "$lt": "<", "$lte": "<="}[op]
+
+	var types string
+	switch v := value.(type) {
+	case string:
+		types = stringTypes
+	case bool:
+		types = boolTypes
+	case time.Time:
+		path, value, types = path+".$date", v.UTC().Format(dateFormat), stringTypes
+	case bson.ObjectId:
+		path, value, types = path+".$oid", v.Hex(), stringTypes
+	default:
+		if _, ok := document.Number(value); !ok {
+			return "", nil, fmt.Errorf("%w %s on a %T", document.ErrUnsupported, op, value)
+		}
+		types = numberTypes
+	}
+	j, err := jsonValue(value)
+	if err != nil {
+		return "", nil, err
+	}
+	var conditions []string
+	var args []interface{}
+	for _, p := range jsonPaths(path) {
+		if isWildcard(p) {
+			continue
+		}
+		conditions = append(conditions, "JSON_TYPE("+extract+") IN "+types+" AND "+extract+" "+operator+" CAST(? AS JSON)")
+		args = append(args, p, p, j)
+	}
+	return "COALESCE(" + strings.Join(conditions, " OR ") + ", FALSE)", args, nil
+}
+
+// set : Function translates update into the SQL expression of the new doc column and its arguments
+// update is either a replacement document, which keeps the stored _id, or operators among
+// $set, $unset, $inc and $push. $setOnInsert only applies to the document inserted by an upsert
+func set(update bson.M) (string, []interface{}, error) {
+	if document.IsReplacement(update) {
+		replacement := document.Clone(update)
+		delete(replacement, "_id")
+		j, err := jsonValue(replacement)
+		if err != nil {
+			return "", nil, err
+		}
+		return "JSON_SET(CAST(? AS JSON), '$._id', JSON_EXTRACT(doc, '$._id'))", []interface{}{j}, nil
+	}
+
+	expr := "doc"
+	var args []interface{}
+	var created []string
+
+	//embedded documents of the updated paths are created first, JSON_SET doesn't create them
+	for _, op := range []string{"$set", "$inc", "$push"} {
+		if fields, ok := update[op].(bson.M); ok {
+			for path := range fields {
+				created = append(created, parents(path)...)
+			}
+		}
+	}
+	if len(created) > 0 {
+		sort.Strings(created)
+		var pairs []string
+		for i, parent := range created {
+			if i > 0 && created[i-1] == parent {
+				continue
+			}
+			p := jsonPath(parent)
+			pairs = append(pairs, "?, COALESCE("+extract+", JSON_OBJECT())")
+			args = append(args, p, p)
+		}
+		expr = "JSON_SET(" + expr + ", " + strings.Join(pairs, ", ") + ")"
+	}
+
+	for _, op := range sortedKeys(update) {
+		fields, ok := update[op].(bson.M)
+		if !ok {
+			return "", nil, fmt.Errorf("%s needs a document", op)
+		}
+		paths := sortedKeys(fields)
+		for _, path := range paths {
+			if path == "_id" || strings.HasPrefix(path, "_id.") {
+				return "", nil, document.ErrImmutableID
+			}
+		}
+
+		var pairs []string
+		switch op {
+		case "$set":
+			for _, path := range paths {
+				j, err := jsonValue(fields[path])
+				if err != nil {
+					return "", nil, err
+				}
+				pairs = append(pairs, "?, CAST(? AS JSON)")
+				args = append(args, jsonPath(path), j)
+			}
+			expr = "JSON_SET(" + expr + ", " + strings.Join(pairs, ", ") + ")"
+		case "$unset":
+			for _, path := range paths {
+				pairs = append(pairs, "?")
+				args = append(args, jsonPath(path))
+			}
+			expr = "JSON_REMOVE(" + expr + ", " + strings.Join(pairs, ", ") + ")"
+		case "$inc":
+			for _, path := range paths {
+				n, ok := increment(fields[path])
+				if !ok {
+					return "", nil, fmt.Errorf("$inc of %s needs a number", path)
+				}
+				p := jsonPath(path)
+				pairs = append(pairs, "?, COALESCE("+extract+", 0) + ?")
+				args = append(args, p, p, n)
+			}
+			expr = "JSON_SET(" + expr + ", " + strings.Join(pairs, ", ") + ")"
+		case "$push":
+			for _, path := range paths {
+				items := []interface{}{fields[path]}
+				if operators, ok := document.Operators(fields[path]); ok {
+					each, ok := operators["$each"].([]interface{})
+					if !ok || len(operators) > 1 {
+						return "", nil, fmt.Errorf("%w in $push, only $each is supported", document.ErrUnsupported)
+					}
+					items = each
+				}
+				j, err := jsonValue(items)
+				if err != nil {
+					return "", nil, err
+				}
+				p := jsonPath(path)
+				pairs = append(pairs, "?, IF(JSON_TYPE("+extract+") = 'ARRAY', JSON_MERGE_PRESERVE("+extract+", CAST(? AS JSON)), CAST(? AS JSON))")
+				args = append(args, p, p, p, j, j)
+			}
+			expr = "JSON_SET(" + expr + ", " + strings.Join(pairs, ", ") + ")"
+		case "$setOnInsert":
+		default:
+			return "", nil, fmt.Errorf("%w %s", document.ErrUnsupported, op)
+		}
+	}
+	return expr, args, nil
+}
+
+// parents : Function returns the paths of the embedded documents holding path, i.e. "a", "a.b" for "a.b.c"
+func parents(path string) []string {
+	keys := strings.Split(path, ".")
+	result := make([]string, 0, len(keys)-1)
+	for i := 1; i < len(keys); i++ {
+		result = append(result, strings.Join(keys[:i], "."))
+	}
+	return result
+}
+
+// jsonPath : Function converts a dotted field path into a MySQL JSON path, i.e. $."address"."city"
+func jsonPath(path string) string {
+	var p strings.Builder
+	p.WriteString("$")
+	for _, key := range strings.Split(path, ".") {
+		p.WriteString(`."`)
+		p.WriteString(strings.ReplaceAll(strings.ReplaceAll(key, `\`, `\\`), `"`, `\"`))
+		p.WriteString(`"`)
+	}
+	return p.String()
+}
+
+// jsonPaths : Function returns the MySQL JSON paths a dotted path is read at by a query, jsonPath(path) first.
+// The others go into the arrays the path may cross like MongoDB does, into each of their elements i.e. $."items"[*]."name"
+// or into the element of a numeric key i.e. $."items"[0]."name"
+func jsonPaths(path string) []string {
+	keys := strings.Split(path, ".")
+	paths := []string{jsonPath(keys[0])}
+	for _, key := range keys[1:] {
+		member := jsonPath(key)[1:]
+		var next []string
+		for _, p := range paths {
+			next = append(next, p+member)
+		}
+		for _, p := range paths {
+			next = append(next, p+"[*]"+member)
+			if i, err := strconv.Atoi(key); err == nil && i >= 0 {
+				next = append(next, p+"["+key+"]")
+			}
+		}
+		paths = next
+	}
+	return paths
+}
+
+// isWildcard : Function reports if the JSON path p selects several values, which JSON_EXTRACT returns as an array
+func isWildcard(p string) bool {
+	return strings.Contains(p, "[*]")
+}
+
+// jsonValue : Function encodes a value as the JSON stored in the doc column
+func jsonValue(value interface{}) (string, error) {
+	data, err := bson.MarshalJSON(normalize(value))
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", gomongo.ErrorValidation, err)
+	}
+	return string(bytes.TrimSpace(data)), nil
+}
+
+// normalize : Function prepares a value for jsonValue, dates being written with a fixed width
+// so they compare as strings and 64 bits integers as plain JSON numbers
+func normalize(value interface{}) interface{} {
+	switch v := value.(type) {
+	case bson.M:
+		result := make(bson.M, len(v))
+		for key, item := range v {
+			result[key] = normalize(item)
+		}
+		return result
+	case []interface{}:
+		result := make([]interface{}, len(v))
+		for i, item := range v {
+			result[i] = normalize(item)
+		}
+		return result
+	case time.Time:
+		return bson.M{"$date": v.UTC().Format(dateFormat)}
+	case int64:
+		return int(v)
+	}
+	return value
+}
+
+// increment : Function returns the argument bound for an $inc of value, an int64 for the integers
+// so MySQL keeps an integer field an integer, a float64 for the other numbers
+func increment(value interface{}) (interface{}, bool) {
+	switch v := reflect.ValueOf(value); v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int(), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return int64(v.Uint()), true
+	}
+	return document.Number(value)
+}
+
+// idKey : Function returns the value of the id column for an _id, numbers being keyed the same whatever their Go type
+func idKey(id interface{}) (string, error) {
+	if n, ok := document.Number(id); ok {
+		id = n
+	}
+	return jsonValue(id)
+}
+
+// sortedKeys : Function returns the keys of m sorted, so the generated SQL is always the same
+func sortedKeys(m bson.M) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,191 @@
+package gomongo
+
+import (
+	"context"
+
+	mgo "github.com/globalsign/mgo"
+)
+
+// operationsV2 adapts the OperationsV3 of a Connection or a Collection to OperationsV2
+type operationsV2 struct {
+	ops OperationsV3
+}
+
+// AsOperationsV2 : Function adapts a Connection or a Collection to the OperationsV2 interface of the mgo releases
+// The *WriteResult of the writes are converted to the mgo.ChangeInfo they returned
+// BulkInsert returns an empty mgo.BulkResult, mgo reported no counts for inserts and only a nil error tells they succeeded
+// Input Parameters
+//		ops (OperationsV3) : *Connection, *Collection or a wrapper of them
+// Output Parameters
+//		OperationsV2 : the operations with their V2 signatures
+func AsOperationsV2(ops OperationsV3) OperationsV2 {
+	return &operationsV2{ops: ops}
+}
+
+// changeInfo : Function converts result into the mgo.ChangeInfo of V2, nil when there is none
+func changeInfo(result *WriteResult) *mgo.ChangeInfo {
+	if result == nil {
+		return nil
+	}
+	return &mgo.ChangeInfo{Updated: result.Modified, Removed: result.Removed, Matched: result.Matched, UpsertedId: result.UpsertedId}
+}
+
+// sendCallback : Function sends data and err on callback like the V2 Async operations did
+func sendCallback(callback chan *Callback, data interface{}, err error) {
+	cb := new(Callback)
+	cb.Data = data
+	cb.Error = err
+	callback <- cb
+}
+
+func (v2 *operationsV2) BulkInsert(bulkInsertStruct *BulkInsertStruct) (*mgo.BulkResult, error) {
+	return v2.BulkInsertCtx(context.Background(), bulkInsertStruct)
+}
+
+func (v2 *operationsV2) BulkInsertCtx(ctx context.Context, bulkInsertStruct *BulkInsertStruct) (*mgo.BulkResult, error) {
+	if _, err := v2.ops.BulkInsertCtx(ctx, bulkInsertStruct); err != nil {
+		return nil, err
+	}
+	//Matched and Modified count updated documents, an insert has none
+	return &mgo.BulkResult{}, nil
+}
+
+func (v2 *operationsV2) Insert(insertStruct *InsertStruct) error {
+	return v2.InsertCtx(context.Background(), insertStruct)
+}
+
+func (v2 *operationsV2) InsertCtx(ctx context.Context, insertStruct *InsertStruct) error {
+	_, err := v2.ops.InsertCtx(ctx, insertStruct)
+	return err
+}
+
+func (v2 *operationsV2) InsertAsync(insertStruct *InsertStruct, callback chan *Callback) {
+	sendCallback(callback, nil, v2.Insert(insertStruct))
+}
+
+func (v2 *operationsV2) Update(updateStruct *UpdateStruct) error {
+	return v2.UpdateCtx(context.Background(), updateStruct)
+}
+
+func (v2 *operationsV2) UpdateCtx(ctx context.Context, updateStruct *UpdateStruct) error {
+	_, err := v2.ops.UpdateCtx(ctx, updateStruct)
+	return err
+}
+
+func (v2 *operationsV2) UpdateAsync(updateStruct *UpdateStruct, callback chan *Callback) {
+	sendCallback(callback, nil, v2.Update(updateStruct))
+}
+
+func (v2 *operationsV2) Upsert(upsertStruct *UpsertStruct) (*mgo.ChangeInfo, error) {
+	return v2.UpsertCtx(context.Background(), upsertStruct)
+}
+
+func (v2 *operationsV2) UpsertCtx(ctx context.Context, upsertStruct *UpsertStruct) (*mgo.ChangeInfo, error) {
+	result, err := v2.ops.UpsertCtx(ctx, upsertStruct)
+	return changeInfo(result), err
+}
+
+func (v2 *operationsV2) UpsertAsync(upsertStruct *UpsertStruct, callback chan *Callback) {
+	info, err := v2.Upsert(upsertStruct)
+	sendCallback(callback, info, err)
+}
+
+func (v2 *operationsV2) UpdateOne(updateOneStruct UpdateOneStruct) error {
+	return v2.UpdateOneCtx(context.Background(), updateOneStruct)
+}
+
+func (v2 *operationsV2) UpdateOneCtx(ctx context.Context, updateOneStruct UpdateOneStruct) error {
+	_, err := v2.ops.UpdateOneCtx(ctx, updateOneStruct)
+	return err
+}
+
+func (v2 *operationsV2) UpdateAll(updateAllStruct UpdateAllStruct) (*mgo.ChangeInfo, error) {
+	return v2.UpdateAllCtx(context.Background(), updateAllStruct)
+}
+
+func (v2 *operationsV2) UpdateAllCtx(ctx context.Context, updateAllStruct UpdateAllStruct) (*mgo.ChangeInfo, error) {
+	result, err := v2.ops.UpdateAllCtx(ctx, updateAllStruct)
+	return changeInfo(result), err
+}
+
+func (v2 *operationsV2) UpdateAllAsync(updateAllStruct UpdateAllStruct, callback chan *Callback) {
+	info, err := v2.UpdateAll(updateAllStruct)
+	sendCallback(callback, info, err)
+}
+
+func (v2 *operationsV2) UpsertAll(upsertAllStruct *UpsertAllStruct) (*mgo.ChangeInfo, error) {
+	return v2.UpsertAllCtx(context.Background(), upsertAllStruct)
+}
+
+func (v2 *operationsV2) UpsertAllCtx(ctx context.Context, upsertAllStruct *UpsertAllStruct) (*mgo.ChangeInfo, error) {
+	result, err := v2.ops.UpsertAllCtx(ctx, upsertAllStruct)
+	return changeInfo(result), err
+}
+
+func (v2 *operationsV2) UpsertAllAsync(upsertAllStruct *UpsertAllStruct, callback chan *Callback) {
+	info, err := v2.UpsertAll(upsertAllStruct)
+	sendCallback(callback, info, err)
+}
+
+func (v2 *operationsV2) FindByID(findByIDStruct *FindByIDStruct) (interface{}, error) {
+	return v2.ops.FindByID(findByIDStruct)
+}
+
+func (v2 *operationsV2) FindByIDCtx(ctx context.Context, findByIDStruct *FindByIDStruct) (interface{}, error) {
+	return v2.ops.FindByIDCtx(ctx, findByIDStruct)
+}
+
+func (v2 *operationsV2) FindByIDAsync(findByIDStruct *FindByIDStruct, callback chan *Callback) {
+	v2.ops.FindByIDAsync(findByIDStruct, callback)
+}
+
+func (v2 *operationsV2) Find(findStruct *FindStruct) ([]interface{}, error) {
+	return v2.ops.Find(findStruct)
+}
+
+func (v2 *operationsV2) FindCtx(ctx context.Context, findStruct *FindStruct) ([]interface{}, error) {
+	return v2.ops.FindCtx(ctx, findStruct)
+}
+
+func (v2 *operationsV2) FindAsync(findStruct *FindStruct, callback chan *Callback) {
+	v2.ops.FindAsync(findStruct, callback)
+}
+
+func (v2 *operationsV2) FindAll(findAllStruct *FindAllStruct) ([]interface{}, error) {
+	return v2.ops.FindAll(findAllStruct)
+}
+
+func (v2 *operationsV2) FindAllCtx(ctx context.Context, findAllStruct *FindAllStruct) ([]interface{}, error) {
+	return v2.ops.FindAllCtx(ctx, findAllStruct)
+}
+
+func (v2 *operationsV2) FindAllAsync(findAllStruct *FindAllStruct, callback chan *Callback) {
+	v2.ops.FindAllAsync(findAllStruct, callback)
+}
+
+func (v2 *operationsV2) Remove(removeStruct *RemoveStruct) error {
+	return v2.RemoveCtx(context.Background(), removeStruct)
+}
+
+func (v2 *operationsV2) RemoveCtx(ctx context.Context, removeStruct *RemoveStruct) error {
+	_, err := v2.ops.RemoveCtx(ctx, removeStruct)
+	return err
+}
+
+func (v2 *operationsV2) RemoveAsync(removeStruct *RemoveStruct, callback chan *Callback) {
+	sendCallback(callback, nil, v2.Remove(removeStruct))
+}
+
+func (v2 *operationsV2) RemoveAll(removeAllStruct *RemoveAllStruct) (*mgo.ChangeInfo, error) {
+	return v2.RemoveAllCtx(context.Background(), removeAllStruct)
+}
+
+func (v2 *operationsV2) RemoveAllCtx(ctx context.Context, removeAllStruct *RemoveAllStruct) (*mgo.ChangeInfo, error) {
+	result, err := v2.ops.RemoveAllCtx(ctx, removeAllStruct)
+	return changeInfo(result), err
+}
+
+func (v2 *operationsV2) RemoveAllAsync(removeAllStruct *RemoveAllStruct, callback chan *Callback) {
+	info, err := v2.RemoveAll(removeAllStruct)
+	sendCallback(callback, info, err)
+}
@@ -0,0 +1,247 @@
+package gomongo
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/globalsign/mgo/bson"
+
+	"github.com/alishavirani/gomongo/internal/document"
+)
+
+// Page is one page of the records returned by FindPage
+type Page struct {
+	Records   []interface{} //records of the page, in the order of the sort key
+	NextToken string        //token of the next page, empty on the last page
+}
+
+// pageToken is the content of a page token: the sort key, the digest of the listing and the position of the last record of a page
+type pageToken struct {
+	Sort  string      `bson:"s"`
+	Hash  []byte      `bson:"h"`
+	Value interface{} `bson:"v"`
+	Id    interface{} `bson:"i"`
+}
+
+// FindPage : Function returns a page of the records matching the query/criteria, ordered on a stable key
+// Unlike skip and limit, the next page starts right after the last record of the previous one,
+// so a page costs the same whatever its position and records inserted meanwhile aren't returned twice
+// The records are ordered on Sort then _id, Sort must hold a value of the same type in every record where it is set,
+// the records where it is null or missing come first as in MongoDB
+// Input Parameters
+//		*PageStruct (Struct) :
+// 			Query(bson Object) : Criteria as per the find should execute
+//			Sort(string) : key of the pages, "-" for descending, _id when empty
+//			Limit(int) : records per page, DefaultPageSize when 0
+//			Token(string) : NextToken of the previous page, empty for the first page
+// Output Parameters
+// 		*Page : the records and the token of the next page
+// 		error : ErrorValidation if the token is invalid or was issued for another Query or Sort, else error if it failed
+func (c *Collection) FindPage(pageStruct *PageStruct) (*Page, error) {
+	return c.FindPageCtx(context.Background(), pageStruct)
+}
+
+// FindPageCtx : Function returns a page of the records matching the query/criteria, aborting when ctx is done
+// Input Parameters
+//		ctx (context.Context) : cancellation and deadline for the operation
+//		*PageStruct (Struct) :
+// 			Query(bson Object) : Criteria as per the find should execute
+//			Sort(string) : key of the pages, "-" for descending, _id when empty
+//			Limit(int) : records per page, DefaultPageSize when 0
+//			Token(string) : NextToken of the previous page, empty for the first page
+// Output Parameters
+// 		*Page : the records and the token of the next page
+// 		error : ErrorValidation if the token is invalid or was issued for another Query or Sort, else error if it failed
+func (c *Collection) FindPageCtx(ctx context.Context, pageStruct *PageStruct) (*Page, error) {
+	page, err := c.findPage(ctx, pageStruct)
+	if err != nil {
+		log.Println(err)
+		return nil, newError("FindPage", c.Name, err)
+	}
+	return page, nil
+}
+
+// FindPageAsync : Function returns a page of the records matching the query/criteria
+// Input Parameters
+//		*PageStruct (Struct) :
+// 			Query(bson Object) : Criteria as per the find should execute
+//			Token(string) : NextToken of the previous page, empty for the first page
+//			callback (channel) : which returns data to goroutine
+// Output Parameters
+// 		callback(data, error) : returns the *Page, error to channel
+func (c *Collection) FindPageAsync(pageStruct *PageStruct, callback chan *Callback) {
+	page, err := c.FindPage(pageStruct)
+	cb := new(Callback)
+	cb.Data = page
+	cb.Error = err
+	callback <- cb
+}
+
+// findPage : Function reads one record more than the page holds, to know if there is a next page
+func (c *Collection) findPage(ctx context.Context, pageStruct *PageStruct) (*Page, error) {
+	sortField := strings.TrimSpace(pageStruct.Sort)
+	if len(sortField) == 0 {
+		sortField = "_id"
+	}
+	keys, err := sortKeys([]string{sortField})
+	if err != nil {
+		return nil, err
+	}
+	key := keys[0]
+	limit := pageStruct.Limit
+	if limit == 0 {
+		limit = DefaultPageSize
+	}
+	if limit < 0 {
+		return nil, fmt.Errorf("%w: Limit can't be negative", ErrorValidation)
+	}
+
+	hash, err := pageHash(pageStruct.Query, sortField)
+	if err != nil {
+		return nil, err
+	}
+	query := pageStruct.Query
+	if len(pageStruct.Token) > 0 {
+		token, err := decodePageToken(pageStruct.Token, sortField, hash)
+		if err != nil {
+			return nil, err
+		}
+		query = afterToken(query, key, token)
+	}
+
+	sort := []string{sortField}
+	if key.Path != "_id" {
+		//_id breaks the ties, so records sharing a sort value are never split or repeated across pages
+		sort = append(sort, "_id")
+		if key.Desc {
+			sort[1] = "-_id"
+		}
+	}
+
+	var records []interface{}
+	err = c.findRecords(ctx, query, pageFields(pageStruct.Fields, key.Path), FindOptions{Sort: sort, Limit: limit + 1}, &records)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &Page{Records: records}
+	if len(records) > limit {
+		page.Records = records[:limit]
+		last, _ := page.Records[limit-1].(bson.M)
+		value, _ := document.Get(last, key.Path)
+		page.NextToken, err = encodePageToken(pageToken{Sort: sortField, Hash: hash, Value: value, Id: last["_id"]})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return page, nil
+}
+
+// afterToken : Function restricts query to the records sorted after the position of token
+// Null and missing values sort before any other, a comparison never matches them so they are selected on their own
+func afterToken(query bson.M, key document.SortKey, token *pageToken) bson.M {
+	op := "$gt"
+	if key.Desc {
+		op = "$lt"
+	}
+	var after bson.M
+	switch {
+	case key.Path == "_id":
+		after = bson.M{"_id": bson.M{op: token.Value}}
+	case token.Value == nil:
+		after = bson.M{key.Path: nil, "_id": bson.M{op: token.Id}}
+		if !key.Desc {
+			//ascending, every record with a value comes after the null ones
+			after = bson.M{"$or": []interface{}{after, bson.M{key.Path: bson.M{"$ne": nil}}}}
+		}
+	default:
+		or := []interface{}{
+			bson.M{key.Path: bson.M{op: token.Value}},
+			bson.M{key.Path: token.Value, "_id": bson.M{op: token.Id}},
+		}
+		if key.Desc {
+			//descending, the null records come after every record with a value
+			or = append(or, bson.M{key.Path: nil})
+		}
+		after = bson.M{"$or": or}
+	}
+	if len(query) == 0 {
+		return after
+	}
+	return bson.M{"$and": []interface{}{query, after}}
+}
+
+// pageFields : Function makes sure a projection keeps the sort key and _id, the token is built from them
+func pageFields(fields bson.M, path string) bson.M {
+	if len(fields) == 0 {
+		return fields
+	}
+	projection := bson.M{}
+	include := false
+	for field, value := range fields {
+		included := value == true
+		if n, ok := document.Number(value); ok && n != 0 {
+			included = true
+		}
+		if !included && (field == path || field == "_id") {
+			continue
+		}
+		projection[field] = value
+		include = include || included
+	}
+	if include {
+		projection[path] = 1
+	}
+	return projection
+}
+
+// encodePageToken : Function encodes token as an opaque url safe string
+func encodePageToken(token pageToken) (string, error) {
+	data, err := bson.Marshal(token)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrorValidation, err)
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// pageHash : Function digests the query and the sort of a listing, a token only continues the listing it was issued for
+// The JSON of a bson.M has its keys sorted, so the digest doesn't depend on the order of the map
+func pageHash(query bson.M, sortField string) ([]byte, error) {
+	if len(query) == 0 {
+		query = nil
+	}
+	data, err := bson.MarshalJSON(query)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrorValidation, err)
+	}
+	digest := sha256.New()
+	digest.Write(data)
+	digest.Write([]byte{0})
+	digest.Write([]byte(sortField))
+	return digest.Sum(nil)[:12], nil
+}
+
+// decodePageToken : Function decodes a token returned by encodePageToken, which must have been issued for sortField
+// and the listing of hash
+func decodePageToken(value string, sortField string, hash []byte) (*pageToken, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid page token", ErrorValidation)
+	}
+	token := new(pageToken)
+	if err := bson.Unmarshal(data, token); err != nil {
+		return nil, fmt.Errorf("%w: invalid page token", ErrorValidation)
+	}
+	if token.Sort != sortField {
+		return nil, fmt.Errorf("%w: the page token was issued for the sort %q, not %q", ErrorValidation, token.Sort, sortField)
+	}
+	if !bytes.Equal(token.Hash, hash) {
+		return nil, fmt.Errorf("%w: the page token was issued for another query", ErrorValidation)
+	}
+	return token, nil
+}
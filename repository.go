@@ -0,0 +1,151 @@
+package gomongo
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"reflect"
+
+	"github.com/globalsign/mgo/bson"
+)
+
+// Repository is a typed view of a collection
+// Records are decoded straight into T instead of being returned as bson.M values,
+// everything not covered here is still available through the embedded handle
+type Repository[T any] struct {
+	*Collection
+}
+
+// DecodeError is returned by a Repository when a stored record can't be decoded into T
+// Note that the bson decoder skips fields whose stored type doesn't fit the Go field,
+// so DecodeError reports records which can't be decoded at all (a failing SetBSON, a non document value, etc)
+type DecodeError struct {
+	Collection string      //collection name
+	Id         interface{} //_id of the record, nil when unknown
+	Type       string      //Go type the record was decoded into
+	Err        error       //error returned by the decoder
+}
+
+func (e *DecodeError) Error() string {
+	return fmt.Sprintf("gomongo: cannot decode record %v of collection %q into %s: %v", e.Id, e.Collection, e.Type, e.Err)
+}
+
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+// NewRepository : Function returns a typed repository on top of the named collection of conn
+// Input Parameters
+//		conn(*Connection) : the connection to use
+//		collection(string) : collection name
+// Output Parameters
+//		*Repository[T] : repository decoding records into T
+func NewRepository[T any](conn *Connection, collection string) *Repository[T] {
+	return &Repository[T]{Collection: conn.C(collection)}
+}
+
+// Insert : Function inserts doc into the collection, the result holds its _id
+func (r *Repository[T]) Insert(doc T) (*WriteResult, error) {
+	return r.InsertCtx(context.Background(), doc)
+}
+
+// InsertCtx : Function inserts doc into the collection, aborting when ctx is done
+func (r *Repository[T]) InsertCtx(ctx context.Context, doc T) (*WriteResult, error) {
+	return r.Collection.InsertCtx(ctx, &InsertStruct{Data: doc})
+}
+
+// Update : Function replaces the record having the given id with doc
+func (r *Repository[T]) Update(id interface{}, doc T) (*WriteResult, error) {
+	return r.UpdateCtx(context.Background(), id, doc)
+}
+
+// UpdateCtx : Function replaces the record having the given id with doc, aborting when ctx is done
+func (r *Repository[T]) UpdateCtx(ctx context.Context, id interface{}, doc T) (*WriteResult, error) {
+	return r.Collection.UpdateCtx(ctx, &UpdateStruct{Id: id, Data: doc})
+}
+
+// FindByID : Function finds and returns record by ID
+// Output Parameters
+//		*T : the decoded record
+//		error : ErrorNotFound if there is no record with the ID, *DecodeError if the record can't be decoded
+func (r *Repository[T]) FindByID(findByIDStruct *FindByIDStruct) (*T, error) {
+	return r.FindByIDCtx(context.Background(), findByIDStruct)
+}
+
+// FindByIDCtx : Function finds and returns record by ID, aborting when ctx is done
+func (r *Repository[T]) FindByIDCtx(ctx context.Context, findByIDStruct *FindByIDStruct) (*T, error) {
+	var raw bson.Raw
+	err := r.findByID(ctx, findByIDStruct, &raw)
+	if err != nil {
+		log.Println(err)
+		return nil, newError("FindByID", r.Name, err)
+	}
+	doc, err := r.decode(raw)
+	if err != nil {
+		return nil, err
+	}
+	return &doc, nil
+}
+
+// Find : Function finds the records matching the query/criteria of findStruct
+func (r *Repository[T]) Find(findStruct *FindStruct) ([]T, error) {
+	return r.FindCtx(context.Background(), findStruct)
+}
+
+// FindCtx : Function finds the records matching the query/criteria of findStruct, aborting when ctx is done
+func (r *Repository[T]) FindCtx(ctx context.Context, findStruct *FindStruct) ([]T, error) {
+	var raws []bson.Raw
+	err := r.find(ctx, findStruct, &raws)
+	if err != nil {
+		log.Println(err)
+		return nil, newError("Find", r.Name, err)
+	}
+	return r.decodeAll(raws)
+}
+
+// FindAll : Function finds all the records of the collection
+func (r *Repository[T]) FindAll(findAllStruct *FindAllStruct) ([]T, error) {
+	return r.FindAllCtx(context.Background(), findAllStruct)
+}
+
+// FindAllCtx : Function finds all the records of the collection, aborting when ctx is done
+func (r *Repository[T]) FindAllCtx(ctx context.Context, findAllStruct *FindAllStruct) ([]T, error) {
+	var raws []bson.Raw
+	err := r.findAll(ctx, findAllStruct, &raws)
+	if err != nil {
+		log.Println(err)
+		return nil, newError("FindAll", r.Name, err)
+	}
+	return r.decodeAll(raws)
+}
+
+// decode : Function decodes a single raw record into T
+func (r *Repository[T]) decode(raw bson.Raw) (T, error) {
+	var doc T
+	if err := raw.Unmarshal(&doc); err != nil {
+		var key struct {
+			Id interface{} `bson:"_id"`
+		}
+		raw.Unmarshal(&key)
+		return doc, &DecodeError{
+			Collection: r.Name,
+			Id:         key.Id,
+			Type:       reflect.TypeOf((*T)(nil)).Elem().String(),
+			Err:        err,
+		}
+	}
+	return doc, nil
+}
+
+// decodeAll : Function decodes every raw record into T, stopping at the first failure
+func (r *Repository[T]) decodeAll(raws []bson.Raw) ([]T, error) {
+	docs := make([]T, 0, len(raws))
+	for _, raw := range raws {
+		doc, err := r.decode(raw)
+		if err != nil {
+			return nil, err
+		}
+		docs = append(docs, doc)
+	}
+	return docs, nil
+}
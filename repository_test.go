@@ -0,0 +1,70 @@
+package gomongo
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/globalsign/mgo/bson"
+	"github.com/stretchr/testify/assert"
+)
+
+//mock for testing, refuses every stored value
+type brokenField struct{}
+
+func (brokenField) SetBSON(raw bson.Raw) error {
+	return errors.New("broken field")
+}
+
+type brokenPerson struct {
+	FirstName string
+	Broken    brokenField
+}
+
+func rawRecord(t *testing.T, doc bson.M) bson.Raw {
+	data, err := bson.Marshal(doc)
+	assert.Nil(t, err)
+	return bson.Raw{Kind: 0x03, Data: data}
+}
+
+func TestRepositoryDecode(t *testing.T) {
+	repo := &Repository[Person]{Collection: &Collection{Name: "users"}}
+
+	people, err := repo.decodeAll([]bson.Raw{
+		rawRecord(t, bson.M{"firstname": "Amulya", "age": 26}),
+		rawRecord(t, bson.M{"firstname": "Ratan", "age": 27}),
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(people))
+	assert.Equal(t, "Amulya", people[0].FirstName)
+	assert.Equal(t, 27, people[1].Age)
+}
+
+func TestRepositoryDecodeError(t *testing.T) {
+	repo := &Repository[brokenPerson]{Collection: &Collection{Name: "users"}}
+
+	_, err := repo.decodeAll([]bson.Raw{rawRecord(t, bson.M{"_id": 7, "firstname": "Amulya", "broken": 1})})
+
+	var decodeErr *DecodeError
+	assert.True(t, errors.As(err, &decodeErr))
+	assert.Equal(t, "users", decodeErr.Collection)
+	assert.Equal(t, 7, decodeErr.Id)
+	assert.Equal(t, "gomongo.brokenPerson", decodeErr.Type)
+	assert.EqualError(t, decodeErr.Err, "broken field")
+}
+
+func TestRepository(t *testing.T) {
+	conn, err := ConnectForTest()
+	if err != nil {
+		t.Skip("MongoDB is not reachable: ", err)
+	}
+	defer Close(conn)
+
+	repo := NewRepository[Person](conn, "users")
+	_, err = repo.Insert(Person{FirstName: "AmulyaRepository", Age: 26})
+	assert.Nil(t, err)
+
+	people, err := repo.Find(&FindStruct{Query: bson.M{"firstname": "AmulyaRepository"}})
+	assert.Nil(t, err)
+	assert.NotEmpty(t, people)
+	assert.Equal(t, 26, people[0].Age)
+}
@@ -0,0 +1,472 @@
+// Package sqlite registers the SQLITE driver of gomongo, which keeps the documents of each
+// collection as JSON in a table of a SQLite database. Import it for its side effect:
+//
+//	import _ "github.com/alishavirani/gomongo/sqlite"
+//
+//	db, err := gomongo.Init(gomongo.SQLITE)
+//	conn, err := db.Connect(&gomongo.Config{DbType: gomongo.SQLITE, Database: "app.db"})
+package sqlite
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/globalsign/mgo/bson"
+	sqlite3 "github.com/mattn/go-sqlite3"
+
+	"github.com/alishavirani/gomongo"
+	"github.com/alishavirani/gomongo/internal/document"
+)
+
+func init() {
+	gomongo.Register(gomongo.SQLITE, func() gomongo.DB { return new(SQLite) })
+}
+
+// SQLite is the DB of the SQLITE driver
+type SQLite struct{}
+
+// Connect : Function opens the SQLite database of config
+// Input Parameters
+//		config (*gomongo.Config) : Uri is the data source name given to the SQLite driver
+//			(i.e, "file:app.db?cache=shared"), else Database is the file name, ":memory:" for a private in memory database
+// Output Parameters
+//		*gomongo.Connection : connection whose operations are served by the database
+//		error : gomongo.ErrorInvalidConfig if neither Uri nor Database is set, else the error of the SQLite driver
+func (SQLite) Connect(config *gomongo.Config) (*gomongo.Connection, error) {
+	if config.DbType != gomongo.SQLITE {
+		return nil, gomongo.ErrorInvalidDBType
+	}
+	dsn := config.Uri
+	if len(dsn) == 0 {
+		dsn = config.Database
+	}
+	if len(dsn) == 0 {
+		return nil, fmt.Errorf("%w: the SQLite database file is missing, set Database or Uri", gomongo.ErrorInvalidConfig)
+	}
+
+	db, err := sql.Open("sqlite3", dsn)
+	if err != nil {
+		return nil, err
+	}
+	//SQLite has a single writer, and every connection to ":memory:" would open a different database
+	db.SetMaxOpenConns(1)
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	conn := new(gomongo.Connection)
+	conn.Database = config.Database
+	conn.Store = &Store{db: db, tables: make(map[string]bool)}
+	return conn, nil
+}
+
+// Store keeps every collection in a table (id TEXT PRIMARY KEY, doc TEXT) created on first use,
+// id being the JSON of the _id and doc the document as MongoDB extended JSON
+// Queries on a plain _id use the primary key, the other ones are evaluated on the documents of the table
+type Store struct {
+	db       *sql.DB
+	tablesMu sync.Mutex
+	tables   map[string]bool //tables known to exist
+}
+
+// table : Function returns the quoted table of collection, creating it when missing
+func (s *Store) table(ctx context.Context, collection string) (string, error) {
+	table := `"` + strings.ReplaceAll(collection, `"`, `""`) + `"`
+
+	s.tablesMu.Lock()
+	defer s.tablesMu.Unlock()
+	if !s.tables[collection] {
+		if _, err := s.db.ExecContext(ctx, "CREATE TABLE IF NOT EXISTS "+table+" (id TEXT PRIMARY KEY, doc TEXT NOT NULL)"); err != nil {
+			return "", err
+		}
+		s.tables[collection] = true
+	}
+	return table, nil
+}
+
+// Insert : Function inserts docs in a single transaction, none is inserted if one fails
+func (s *Store) Insert(ctx context.Context, collection string, docs []bson.M) error {
+	table, err := s.table(ctx, collection)
+	if err != nil {
+		return err
+	}
+	return s.transaction(ctx, func(tx *sql.Tx) error {
+		for _, doc := range docs {
+			if err := insert(ctx, tx, table, doc); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Find : Function returns the documents matching query in insertion order
+func (s *Store) Find(ctx context.Context, collection string, query bson.M, skip, limit int) ([]bson.M, error) {
+	table, err := s.table(ctx, collection)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := s.find(ctx, s.db, table, query, skip, limit)
+	if err != nil {
+		return nil, err
+	}
+	docs := make([]bson.M, len(rows))
+	for i, row := range rows {
+		docs[i] = row.doc
+	}
+	return docs, nil
+}
+
+// Count : Function returns the number of documents matching query, counted by SQLite when there is no condition
+func (s *Store) Count(ctx context.Context, collection string, query bson.M) (int, error) {
+	table, err := s.table(ctx, collection)
+	if err != nil {
+		return 0, err
+	}
+	if len(query) > 0 {
+		rows, err := s.find(ctx, s.db, table, query, 0, 0)
+		return len(rows), err
+	}
+	var count int
+	err = s.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM "+table).Scan(&count)
+	return count, err
+}
+
+// Distinct : Function returns the distinct values at path of the documents matching query,
+// selected by SQLite when there is no condition and path is a top level field. The JSON paths of SQLite
+// don't go into arrays, so the dotted paths are read from the documents
+func (s *Store) Distinct(ctx context.Context, collection string, path string, query bson.M) ([]interface{}, error) {
+	table, err := s.table(ctx, collection)
+	if err != nil {
+		return nil, err
+	}
+	p, ok := jsonPath(path)
+	if len(query) > 0 || !ok || strings.Contains(path, ".") {
+		rows, err := s.find(ctx, s.db, table, query, 0, 0)
+		if err != nil {
+			return nil, err
+		}
+		docs := make([]bson.M, len(rows))
+		for i, row := range rows {
+			docs[i] = row.doc
+		}
+		return document.Distinct(docs, path), nil
+	}
+
+	//-> returns the JSON of the value, NULL when the path is missing
+	rows, err := s.db.QueryContext(ctx, "SELECT DISTINCT doc -> ? FROM "+table+" WHERE doc -> ? IS NOT NULL", p, p)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var docs []bson.M
+	for rows.Next() {
+		var data string
+		if err := rows.Scan(&data); err != nil {
+			return nil, err
+		}
+		//the value is decoded in a document, its extended JSON being converted like the one of a document
+		var doc bson.M
+		if err := document.UnmarshalJSON([]byte(`{"v":`+data+`}`), &doc); err != nil {
+			return nil, fmt.Errorf("corrupted value of %s in %s: %v", path, table, err)
+		}
+		docs = append(docs, doc)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	//the arrays are unwound and the values equal for MongoDB but not as JSON (1 and 1.0) merged
+	return document.Distinct(docs, "v"), nil
+}
+
+// Update : Function applies update to the matching documents in a single transaction
+func (s *Store) Update(ctx context.Context, collection string, query, update bson.M, multi, upsert bool) (*gomongo.WriteResult, error) {
+	table, err := s.table(ctx, collection)
+	if err != nil {
+		return nil, err
+	}
+	limit := 1
+	if multi {
+		limit = 0
+	}
+
+	info := new(gomongo.WriteResult)
+	err = s.transaction(ctx, func(tx *sql.Tx) error {
+		rows, err := s.find(ctx, tx, table, query, 0, limit)
+		if err != nil {
+			return err
+		}
+		if len(rows) == 0 && upsert {
+			doc, err := document.Upserted(query, update)
+			if err != nil {
+				return err
+			}
+			if _, ok := doc["_id"]; !ok {
+				doc["_id"] = bson.NewObjectId()
+			}
+			info.UpsertedId = doc["_id"]
+			return insert(ctx, tx, table, doc)
+		}
+		for _, row := range rows {
+			doc, err := document.Apply(row.doc, update, false)
+			if err != nil {
+				return err
+			}
+			info.Matched++
+			//an update leaving the document as it was matches it without modifying it
+			if document.Equal(row.doc, doc) {
+				continue
+			}
+			data, err := encode(doc)
+			if err != nil {
+				return err
+			}
+			if _, err := tx.ExecContext(ctx, "UPDATE "+table+" SET doc = ? WHERE id = ?", data, row.id); err != nil {
+				return err
+			}
+			info.Modified++
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return info, nil
+}
+
+// Remove : Function deletes the matching documents in a single transaction
+func (s *Store) Remove(ctx context.Context, collection string, query bson.M, multi bool) (*gomongo.WriteResult, error) {
+	table, err := s.table(ctx, collection)
+	if err != nil {
+		return nil, err
+	}
+	limit := 1
+	if multi {
+		limit = 0
+	}
+
+	info := new(gomongo.WriteResult)
+	err = s.transaction(ctx, func(tx *sql.Tx) error {
+		rows, err := s.find(ctx, tx, table, query, 0, limit)
+		if err != nil {
+			return err
+		}
+		for _, row := range rows {
+			if _, err := tx.ExecContext(ctx, "DELETE FROM "+table+" WHERE id = ?", row.id); err != nil {
+				return err
+			}
+			info.Matched++
+			info.Removed++
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return info, nil
+}
+
+// FindAndModify : Function changes or removes the first document matching query in the order of sort,
+// in a single transaction which the single connection to the database keeps from interleaving with other writes
+func (s *Store) FindAndModify(ctx context.Context, collection string, query bson.M, sort bson.D, update bson.M, upsert bool) (bson.M, bson.M, error) {
+	table, err := s.table(ctx, collection)
+	if err != nil {
+		return nil, nil, err
+	}
+	var keys []document.SortKey
+	if len(sort) > 0 {
+		if keys, err = document.ParseSort(sort); err != nil {
+			return nil, nil, fmt.Errorf("%w: %v", gomongo.ErrorValidation, err)
+		}
+	}
+
+	var before, after bson.M
+	err = s.transaction(ctx, func(tx *sql.Tx) error {
+		rows, err := s.find(ctx, tx, table, query, 0, 0)
+		if err != nil {
+			return err
+		}
+		if len(rows) == 0 {
+			if update == nil || !upsert {
+				return nil
+			}
+			doc, err := document.Upserted(query, update)
+			if err != nil {
+				return err
+			}
+			if _, ok := doc["_id"]; !ok {
+				doc["_id"] = bson.NewObjectId()
+			}
+			after = doc
+			return insert(ctx, tx, table, doc)
+		}
+
+		docs := make([]bson.M, len(rows))
+		for i, row := range rows {
+			docs[i] = row.doc
+		}
+		row := rows[document.First(docs, keys)]
+		before = row.doc
+		if update == nil {
+			_, err := tx.ExecContext(ctx, "DELETE FROM "+table+" WHERE id = ?", row.id)
+			return err
+		}
+		if after, err = document.Apply(row.doc, update, false); err != nil {
+			return err
+		}
+		data, err := encode(after)
+		if err != nil {
+			return err
+		}
+		_, err = tx.ExecContext(ctx, "UPDATE "+table+" SET doc = ? WHERE id = ?", data, row.id)
+		return err
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return before, after, nil
+}
+
+// Ping : Function checks the database can be reached
+func (s *Store) Ping(ctx context.Context) error {
+	return s.db.PingContext(ctx)
+}
+
+// Close : Function closes the database
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// row is a stored document with its primary key
+type row struct {
+	id  string
+	doc bson.M
+}
+
+// querier is satisfied by *sql.DB and *sql.Tx
+type querier interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+// find : Function returns the rows of table matching query
+func (s *Store) find(ctx context.Context, q querier, table string, query bson.M, skip, limit int) ([]row, error) {
+	statement := "SELECT id, doc FROM " + table
+	var args []interface{}
+	//a plain _id is looked up by primary key
+	if id, ok := query["_id"]; ok {
+		if _, isOperator := document.Operators(id); !isOperator {
+			key, err := idKey(id)
+			if err != nil {
+				return nil, err
+			}
+			statement += " WHERE id = ?"
+			args = append(args, key)
+		}
+	}
+	statement += " ORDER BY rowid"
+
+	rows, err := q.QueryContext(ctx, statement, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []row
+	for rows.Next() {
+		var r row
+		var data string
+		if err := rows.Scan(&r.id, &data); err != nil {
+			return nil, err
+		}
+		if err := document.UnmarshalJSON([]byte(data), &r.doc); err != nil {
+			return nil, fmt.Errorf("corrupted document %s in %s: %v", r.id, table, err)
+		}
+		matched, err := document.Match(r.doc, query)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", gomongo.ErrorValidation, err)
+		}
+		if !matched {
+			continue
+		}
+		if skip > 0 {
+			skip--
+			continue
+		}
+		result = append(result, r)
+		if limit > 0 && len(result) == limit {
+			break
+		}
+	}
+	return result, rows.Err()
+}
+
+// transaction : Function runs op in a transaction, committed when op succeeds
+func (s *Store) transaction(ctx context.Context, op func(tx *sql.Tx) error) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	if err := op(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// insert : Function inserts a single document
+func insert(ctx context.Context, tx *sql.Tx, table string, doc bson.M) error {
+	key, err := idKey(doc["_id"])
+	if err != nil {
+		return err
+	}
+	data, err := encode(doc)
+	if err != nil {
+		return err
+	}
+	_, err = tx.ExecContext(ctx, "INSERT INTO "+table+" (id, doc) VALUES (?, ?)", key, data)
+	var sqliteErr sqlite3.Error
+	if errors.As(err, &sqliteErr) && sqliteErr.Code == sqlite3.ErrConstraint {
+		return fmt.Errorf("%w: _id %s already exists in %s", gomongo.ErrorDuplicateKey, key, table)
+	}
+	return err
+}
+
+// encode : Function returns doc as MongoDB extended JSON, so ObjectIds and dates survive the round trip
+// and document.UnmarshalJSON reads its integers back as integers
+func encode(doc interface{}) (string, error) {
+	data, err := bson.MarshalJSON(doc)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", gomongo.ErrorValidation, err)
+	}
+	return string(bytes.TrimSpace(data)), nil
+}
+
+// jsonPath : Function returns the SQLite JSON path of a dotted path, false if a key can't be quoted in it
+func jsonPath(path string) (string, bool) {
+	var p strings.Builder
+	p.WriteString("$")
+	for _, key := range strings.Split(path, ".") {
+		if strings.Contains(key, `"`) {
+			return "", false
+		}
+		p.WriteString(`."`)
+		p.WriteString(key)
+		p.WriteString(`"`)
+	}
+	return p.String(), true
+}
+
+// idKey : Function returns the primary key of an _id, numbers being keyed the same whatever their Go type
+func idKey(id interface{}) (string, error) {
+	if n, ok := document.Number(id); ok {
+		id = n
+	}
+	return encode(id)
+}
@@ -0,0 +1,280 @@
+package sqlite
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/globalsign/mgo/bson"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/alishavirani/gomongo"
+)
+
+type Person struct {
+	Id        bson.ObjectId `bson:"_id,omitempty"`
+	FirstName string        `bson:"firstName"`
+	Age       int           `bson:"age"`
+}
+
+func connect(t *testing.T) *gomongo.Connection {
+	db, err := gomongo.Init(gomongo.SQLITE)
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+	conn, err := db.Connect(&gomongo.Config{DbType: gomongo.SQLITE, Database: ":memory:"})
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+	t.Cleanup(func() { gomongo.Close(conn) })
+	return conn
+}
+
+func seed(t *testing.T, users *gomongo.Collection) {
+	_, err := users.BulkInsert(&gomongo.BulkInsertStruct{Data: []interface{}{
+		Person{FirstName: "Amulya", Age: 26},
+		Person{FirstName: "Kasyap", Age: 31},
+		bson.M{"_id": 7, "firstName": "Ravi", "age": 40},
+	}})
+	assert.Nil(t, err)
+}
+
+func TestInsertFind(t *testing.T) {
+	users := connect(t).C("users")
+	seed(t, users)
+
+	records, err := users.Find(&gomongo.FindStruct{Query: bson.M{"age": bson.M{"$gt": 30}}})
+	assert.Nil(t, err)
+	assert.Len(t, records, 2)
+
+	records, err = users.Find(&gomongo.FindStruct{Query: bson.M{"firstName": bson.M{"$in": []string{"Amulya", "Ravi"}}}, Options: map[string]int{"isSkip": 1}})
+	assert.Nil(t, err)
+	if assert.Len(t, records, 1) {
+		assert.Equal(t, "Ravi", records[0].(bson.M)["firstName"])
+	}
+
+	records, err = users.Find(&gomongo.FindStruct{Query: bson.M{"firstName": bson.M{"$eq": "Kasyap"}}, Fields: bson.M{"age": 1}})
+	assert.Nil(t, err)
+	if assert.Len(t, records, 1) {
+		assert.Len(t, records[0], 2)
+		assert.IsType(t, bson.ObjectId(""), records[0].(bson.M)["_id"])
+	}
+
+	record, err := users.FindByID(&gomongo.FindByIDStruct{Id: 7})
+	assert.Nil(t, err)
+	assert.Equal(t, "Ravi", record.(bson.M)["firstName"])
+
+	all, err := users.FindAll(&gomongo.FindAllStruct{})
+	assert.Nil(t, err)
+	assert.Len(t, all, 3)
+
+	_, err = users.FindByID(&gomongo.FindByIDStruct{Id: 8})
+	assert.True(t, errors.Is(err, gomongo.ErrorNotFound))
+}
+
+func TestDuplicateKey(t *testing.T) {
+	users := connect(t).C("users")
+	seed(t, users)
+
+	_, err := users.Insert(&gomongo.InsertStruct{Data: bson.M{"_id": 7}})
+	assert.True(t, errors.Is(err, gomongo.ErrorDuplicateKey))
+}
+
+func TestUpdateUpsert(t *testing.T) {
+	conn := connect(t)
+	users := conn.C("users")
+	seed(t, users)
+
+	_, err := users.Update(&gomongo.UpdateStruct{Id: gomongo.StringID("missing"), Data: bson.M{"$set": bson.M{"age": 1}}})
+	assert.True(t, errors.Is(err, gomongo.ErrorNotFound))
+
+	_, err = users.UpdateOne(gomongo.UpdateOneStruct{Query: bson.M{"firstName": "Ravi"}, Data: bson.M{"$inc": bson.M{"age": 2}, "$set": bson.M{"city": "Pune"}}})
+	assert.Nil(t, err)
+
+	info, err := users.UpdateAll(gomongo.UpdateAllStruct{Query: bson.M{"age": bson.M{"$lt": 35}}, Data: bson.M{"$inc": bson.M{"age": 1}}})
+	assert.Nil(t, err)
+	assert.Equal(t, 2, info.Modified)
+
+	//setting the values a record already has matches it without modifying it
+	info, err = users.UpdateAll(gomongo.UpdateAllStruct{Query: bson.M{"firstName": "Ravi"}, Data: bson.M{"$set": bson.M{"firstName": "Ravi"}}})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, info.Matched)
+	assert.Equal(t, 0, info.Modified)
+
+	info, err = users.Upsert(&gomongo.UpsertStruct{Id: gomongo.StringID("anu"), Data: bson.M{"$set": bson.M{"firstName": "Anu", "age": 22}}})
+	assert.Nil(t, err)
+	assert.Equal(t, "anu", info.UpsertedId)
+
+	info, err = users.UpsertAll(&gomongo.UpsertAllStruct{Query: bson.M{"firstName": "Anu"}, Data: bson.M{"$inc": bson.M{"age": 1}}})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, info.Matched)
+
+	repository := gomongo.NewRepository[struct {
+		FirstName string `bson:"firstName"`
+		Age       int    `bson:"age"`
+		City      string `bson:"city"`
+	}](conn, "users")
+	people, err := repository.Find(&gomongo.FindStruct{Query: bson.M{"age": bson.M{"$gte": 23}}})
+	assert.Nil(t, err)
+	ages := map[string]int{}
+	for _, person := range people {
+		ages[person.FirstName] = person.Age
+	}
+	assert.Equal(t, map[string]int{"Amulya": 27, "Kasyap": 32, "Ravi": 42, "Anu": 23}, ages)
+}
+
+func TestRemove(t *testing.T) {
+	users := connect(t).C("users")
+	seed(t, users)
+
+	_, err := users.Remove(&gomongo.RemoveStruct{Query: bson.M{"firstName": "Nobody"}})
+	assert.True(t, errors.Is(err, gomongo.ErrorNotFound))
+
+	info, err := users.Remove(&gomongo.RemoveStruct{Query: bson.M{"_id": 7}})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, info.Removed)
+
+	info, err = users.RemoveAll(&gomongo.RemoveAllStruct{})
+	assert.Nil(t, err)
+	assert.Equal(t, 2, info.Removed)
+}
+
+func TestNumberTypes(t *testing.T) {
+	users := connect(t).C("users")
+	_, err := users.Insert(&gomongo.InsertStruct{Data: bson.M{"_id": 1, "age": 26, "score": 2.5, "views": int64(1) << 60, "ranks": []interface{}{1, 2}}})
+	assert.Nil(t, err)
+	_, err = users.Update(&gomongo.UpdateStruct{Id: 1, Data: bson.M{"$inc": bson.M{"age": 1}}})
+	assert.Nil(t, err)
+
+	//JSON has a single number type, the integers are read back as integers
+	record, err := users.FindByID(&gomongo.FindByIDStruct{Id: 1})
+	assert.Nil(t, err)
+	assert.Equal(t, bson.M{"_id": 1, "age": 27, "score": 2.5, "views": int64(1) << 60, "ranks": []interface{}{1, 2}}, record)
+}
+
+func TestArrayPaths(t *testing.T) {
+	orders := connect(t).C("orders")
+	_, err := orders.BulkInsert(&gomongo.BulkInsertStruct{Data: []interface{}{
+		bson.M{"_id": 1, "items": []interface{}{bson.M{"name": "pen", "price": 5}, bson.M{"name": "ink", "price": 1}}},
+		bson.M{"_id": 2, "items": []interface{}{bson.M{"name": "pad", "price": 3}}},
+		bson.M{"_id": 3, "items": bson.M{"name": "ink", "price": 2}},
+	}})
+	assert.Nil(t, err)
+
+	//a dotted path goes into the documents of the arrays it crosses, like MongoDB does
+	count, err := orders.Count(&gomongo.CountStruct{Query: bson.M{"items.name": "ink"}})
+	assert.Nil(t, err)
+	assert.Equal(t, 2, count)
+	names, err := orders.Distinct(&gomongo.DistinctStruct{Field: "items.name"})
+	assert.Nil(t, err)
+	assert.ElementsMatch(t, []interface{}{"pen", "ink", "pad"}, names)
+
+	records, err := orders.Find(&gomongo.FindStruct{Query: bson.M{"items.price": bson.M{"$gte": 2}}, FindOptions: gomongo.FindOptions{Sort: []string{"items.price"}}})
+	assert.Nil(t, err)
+	var ids []interface{}
+	for _, record := range records {
+		ids = append(ids, record.(bson.M)["_id"])
+	}
+	assert.Equal(t, []interface{}{1, 3, 2}, ids, "an array is sorted on its smallest element")
+}
+
+func TestCountDistinct(t *testing.T) {
+	users := connect(t).C("users")
+	seed(t, users)
+	joined := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	_, err := users.BulkInsert(&gomongo.BulkInsertStruct{Data: []interface{}{
+		bson.M{"firstName": "Anu", "age": 26.0, "tags": []string{"admin", "ops"}, "joined": joined},
+		bson.M{"firstName": "Ravi", "tags": []string{"ops"}, "joined": joined, "address": bson.M{"city": "Pune"}},
+	}})
+	assert.Nil(t, err)
+
+	count, err := users.Count(&gomongo.CountStruct{})
+	assert.Nil(t, err)
+	assert.Equal(t, 5, count)
+	count, err = users.Count(&gomongo.CountStruct{Query: bson.M{"firstName": "Ravi"}})
+	assert.Nil(t, err)
+	assert.Equal(t, 2, count)
+
+	//selected by SQLite, 26 and 26.0 are the same value and the arrays are unwound
+	ages, err := users.Distinct(&gomongo.DistinctStruct{Field: "age"})
+	assert.Nil(t, err)
+	assert.ElementsMatch(t, []interface{}{26, 31, 40}, ages)
+	filtered, err := users.Distinct(&gomongo.DistinctStruct{Field: "age", Query: bson.M{"age": bson.M{"$gt": 0}}})
+	assert.Nil(t, err)
+	assert.ElementsMatch(t, ages, filtered, "the same values as the records read")
+	tags, err := users.Distinct(&gomongo.DistinctStruct{Field: "tags"})
+	assert.Nil(t, err)
+	assert.ElementsMatch(t, []interface{}{"admin", "ops"}, tags)
+	dates, err := users.Distinct(&gomongo.DistinctStruct{Field: "joined"})
+	assert.Nil(t, err)
+	if assert.Len(t, dates, 1) {
+		assert.True(t, joined.Equal(dates[0].(time.Time)))
+	}
+	cities, err := users.Distinct(&gomongo.DistinctStruct{Field: "address.city"})
+	assert.Nil(t, err)
+	assert.Equal(t, []interface{}{"Pune"}, cities)
+
+	names, err := users.Distinct(&gomongo.DistinctStruct{Field: "firstName", Query: bson.M{"age": bson.M{"$lt": 35}}})
+	assert.Nil(t, err)
+	assert.ElementsMatch(t, []interface{}{"Amulya", "Kasyap", "Anu"}, names)
+}
+
+func TestFindOneAndModify(t *testing.T) {
+	jobs := connect(t).C("jobs")
+	for i := 1; i <= 10; i++ {
+		_, err := jobs.Insert(&gomongo.InsertStruct{Data: bson.M{"_id": i, "status": "pending", "priority": i % 4}})
+		assert.Nil(t, err)
+	}
+
+	before, err := jobs.FindOneAndUpdate(&gomongo.FindOneAndUpdateStruct{
+		Query: bson.M{"status": "pending"}, Data: bson.M{"$set": bson.M{"status": "running"}}, Sort: []string{"-priority", "_id"}, Fields: bson.M{"status": 1}})
+	assert.Nil(t, err)
+	assert.Equal(t, bson.M{"_id": 3, "status": "pending"}, before)
+
+	//an update leaving the record as it was still returns it
+	after, err := jobs.FindOneAndUpdate(&gomongo.FindOneAndUpdateStruct{Query: bson.M{"_id": 3}, Data: bson.M{"$set": bson.M{"status": "running"}}, ReturnNew: true})
+	assert.Nil(t, err)
+	assert.Equal(t, "running", after.(bson.M)["status"])
+
+	//every pending job is claimed exactly once by the racing workers
+	var mu sync.Mutex
+	claimed := map[interface{}]int{}
+	var wg sync.WaitGroup
+	for w := 0; w < 4; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				job, err := jobs.FindOneAndUpdate(&gomongo.FindOneAndUpdateStruct{Query: bson.M{"status": "pending"}, Data: bson.M{"$set": bson.M{"status": "running"}}})
+				if errors.Is(err, gomongo.ErrorNotFound) {
+					return
+				}
+				assert.Nil(t, err)
+				mu.Lock()
+				claimed[job.(bson.M)["_id"]]++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Len(t, claimed, 9)
+	for id, n := range claimed {
+		assert.Equal(t, 1, n, "job %v", id)
+	}
+
+	inserted, err := jobs.FindOneAndUpdate(&gomongo.FindOneAndUpdateStruct{Query: bson.M{"_id": 11}, Data: bson.M{"$set": bson.M{"status": "new"}}, Upsert: true, ReturnNew: true})
+	assert.Nil(t, err)
+	assert.Equal(t, bson.M{"_id": 11, "status": "new"}, inserted)
+	removed, err := jobs.FindOneAndDelete(&gomongo.FindOneAndDeleteStruct{Query: bson.M{"_id": 11}})
+	assert.Nil(t, err)
+	assert.Equal(t, bson.M{"_id": 11, "status": "new"}, removed)
+	_, err = jobs.FindOneAndDelete(&gomongo.FindOneAndDeleteStruct{Query: bson.M{"_id": 11}})
+	assert.True(t, errors.Is(err, gomongo.ErrorNotFound))
+}
+
+func TestPing(t *testing.T) {
+	conn := connect(t)
+	assert.Nil(t, conn.Ping())
+	assert.True(t, conn.Healthy())
+}
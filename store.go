@@ -0,0 +1,239 @@
+package gomongo
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/globalsign/mgo/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/alishavirani/gomongo/internal/document"
+)
+
+// Store is implemented by the drivers which don't talk to a MongoDB server (i.e, SQLite)
+// A driver returns a Connection whose Store is set, and every operation of the Connection and of
+// its Collection handles is then served by the Store instead of the MongoDB client.
+// Documents, queries and updates are handed over as bson.M (embedded documents as bson.M, arrays as
+// []interface{}), queries and updates use the MongoDB operators supported by the Store.
+// Documents always carry an _id when they reach Insert.
+type Store interface {
+	// Insert adds docs to collection, ErrorDuplicateKey if one of the _id is taken
+	Insert(ctx context.Context, collection string, docs []bson.M) error
+	// Find returns the documents of collection matching query, skipping skip of them and at most limit (0 for all)
+	Find(ctx context.Context, collection string, query bson.M, skip, limit int) ([]bson.M, error)
+	// Count returns the number of documents of collection matching query
+	Count(ctx context.Context, collection string, query bson.M) (int, error)
+	// Distinct returns the distinct values at the dotted path of the documents of collection matching query,
+	// the elements of an array being values of their own and the documents missing the path skipped
+	Distinct(ctx context.Context, collection string, path string, query bson.M) ([]interface{}, error)
+	// Update applies update to the first (all when multi) documents matching query, inserting one when upsert and nothing matched
+	Update(ctx context.Context, collection string, query, update bson.M, multi, upsert bool) (*WriteResult, error)
+	// Remove deletes the first (all when multi) documents matching query
+	Remove(ctx context.Context, collection string, query bson.M, multi bool) (*WriteResult, error)
+	// FindAndModify applies update to the first document matching query in the order of sort (insertion order when empty),
+	// or removes it when update is nil, inserting one when upsert and nothing matched. No other write may come in between
+	// the find and the change. It returns the document before and after the change, before being nil when nothing matched
+	// and after when the document was removed or nothing was inserted
+	FindAndModify(ctx context.Context, collection string, query bson.M, sort bson.D, update bson.M, upsert bool) (before, after bson.M, err error)
+	// Ping checks that the storage can be reached
+	Ping(ctx context.Context) error
+	// Close releases the storage
+	Close() error
+}
+
+// exec : Function runs mongoOp on a MongoDB connection and storeOp on a connection served by a Store
+func (c *Collection) exec(ctx context.Context, mongoOp func(collection *mongo.Collection) error, storeOp func(store Store) error) error {
+	if c.conn.Store == nil {
+		return c.run(ctx, mongoOp)
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return storeOp(c.conn.Store)
+}
+
+// toDocument : Function converts a struct or map into the bson.M handed to a Store
+func toDocument(value interface{}) (bson.M, error) {
+	if value == nil {
+		return bson.M{}, nil
+	}
+	data, err := bson.Marshal(value)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrorValidation, err)
+	}
+	doc := bson.M{}
+	if err := bson.Unmarshal(data, doc); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrorValidation, err)
+	}
+	return doc, nil
+}
+
+// toDocuments : Function converts the documents to insert, giving an ObjectId to the ones without _id
+func toDocuments(values []interface{}) ([]bson.M, error) {
+	docs := make([]bson.M, len(values))
+	for i, value := range values {
+		doc, err := toDocument(value)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := doc["_id"]; !ok {
+			doc["_id"] = bson.NewObjectId()
+		}
+		docs[i] = doc
+	}
+	return docs, nil
+}
+
+// storeUpdate : Function converts query and update then hands them to store
+func storeUpdate(ctx context.Context, store Store, collection string, query, update interface{}, multi, upsert bool) (*WriteResult, error) {
+	queryDoc, err := toDocument(query)
+	if err != nil {
+		return nil, err
+	}
+	updateDoc, err := toDocument(update)
+	if err != nil {
+		return nil, err
+	}
+	return store.Update(ctx, collection, queryDoc, updateDoc, multi, upsert)
+}
+
+// storeFind : Function finds the documents matching query in store and decodes them into result,
+// a pointer to a slice, or to a single value when one is true (ErrorNotFound if there is none)
+// The documents are sorted here, a Store returns them in insertion order, so they're all read when opts sorts them
+func storeFind(ctx context.Context, store Store, collection string, query interface{}, fields bson.M, opts FindOptions, one bool, result interface{}) error {
+	queryDoc, err := toDocument(query)
+	if err != nil {
+		return err
+	}
+	keys, err := sortKeys(opts.Sort)
+	if err != nil {
+		return err
+	}
+	skip, limit := opts.Skip, opts.Limit
+	if limit < 0 {
+		limit = -limit
+	}
+	if one {
+		limit = 1
+	}
+	if opts.MaxTime > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.MaxTime)
+		defer cancel()
+	}
+
+	var docs []bson.M
+	if len(keys) == 0 {
+		docs, err = store.Find(ctx, collection, queryDoc, skip, limit)
+	} else {
+		docs, err = store.Find(ctx, collection, queryDoc, 0, 0)
+	}
+	if err != nil {
+		return err
+	}
+	if len(keys) > 0 {
+		document.Sort(docs, keys)
+		docs = page(docs, skip, limit)
+	}
+	for i := range docs {
+		docs[i] = document.Project(docs[i], fields)
+	}
+
+	if one {
+		if len(docs) == 0 {
+			return ErrorNotFound
+		}
+		return decodeDocument(docs[0], reflect.ValueOf(result).Elem())
+	}
+	return decodeDocuments(docs, result)
+}
+
+// storeAggregate : Function runs pipeline over the documents of collection in store and decodes them into result, a pointer to a slice
+// The Store only reads the documents, the stages run here, see document.Aggregate for the supported ones
+func storeAggregate(ctx context.Context, store Store, collection string, pipeline []bson.M, result interface{}) error {
+	docs, err := store.Find(ctx, collection, bson.M{}, 0, 0)
+	if err != nil {
+		return err
+	}
+	docs, err = document.Aggregate(docs, pipeline, func(from string) ([]bson.M, error) {
+		return store.Find(ctx, from, bson.M{}, 0, 0)
+	})
+	if err != nil {
+		return err
+	}
+	return decodeDocuments(docs, result)
+}
+
+// storeFindAndModify : Function runs a FindOneAnd operation on store, which finds and changes the record in a single step
+func storeFindAndModify(ctx context.Context, store Store, collection string, m modification) (interface{}, error) {
+	keys, err := sortKeys(m.sort)
+	if err != nil {
+		return nil, err
+	}
+	query, err := toDocument(m.query)
+	if err != nil {
+		return nil, err
+	}
+	var update bson.M
+	if m.op != "FindOneAndDelete" {
+		if update, err = toDocument(m.data); err != nil {
+			return nil, err
+		}
+	}
+
+	before, after, err := store.FindAndModify(ctx, collection, query, sortDocument(keys), update, m.upsert)
+	if err != nil {
+		return nil, err
+	}
+	if before == nil && after == nil {
+		return nil, ErrorNotFound
+	}
+	record := before
+	if m.returnNew {
+		record = after
+	}
+	if record == nil {
+		//inserted by the upsert, which returns the record as it was
+		return nil, nil
+	}
+	return document.Project(record, m.fields), nil
+}
+
+// decodeDocuments : Function decodes docs into result, a pointer to a slice
+func decodeDocuments(docs []bson.M, result interface{}) error {
+	slice := reflect.ValueOf(result).Elem()
+	records := reflect.MakeSlice(slice.Type(), len(docs), len(docs))
+	for i, doc := range docs {
+		if err := decodeDocument(doc, records.Index(i)); err != nil {
+			return err
+		}
+	}
+	slice.Set(records)
+	return nil
+}
+
+// page : Function returns the docs left after skipping skip of them, at most limit (0 for all)
+func page(docs []bson.M, skip, limit int) []bson.M {
+	if skip >= len(docs) {
+		return nil
+	}
+	docs = docs[skip:]
+	if limit > 0 && limit < len(docs) {
+		docs = docs[:limit]
+	}
+	return docs
+}
+
+// decodeDocument : Function stores doc into target, decoding it when target isn't an interface{}
+func decodeDocument(doc bson.M, target reflect.Value) error {
+	if target.Kind() == reflect.Interface {
+		target.Set(reflect.ValueOf(doc))
+		return nil
+	}
+	data, err := bson.Marshal(doc)
+	if err != nil {
+		return err
+	}
+	return bson.Unmarshal(data, target.Addr().Interface())
+}
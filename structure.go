@@ -1,8 +1,14 @@
 package gomongo
 
 import (
+	"context"
+	"sync"
+	"time"
+
 	mgo "github.com/globalsign/mgo"
 	"github.com/globalsign/mgo/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 type Callback struct {
@@ -14,49 +20,212 @@ type DB interface {
 	Connect(*Config) (*Connection, error)
 }
 
-type Operations interface {
-	BulkInsertStruct(*BulkInsertStruct)
+// OperationsV2 is the set of document operations of the releases built on mgo, kept as it was for the callers
+// depending on it, AsOperationsV2 adapts a Connection or a Collection to it
+// A change of the method set gets a new version, the released versions are frozen
+type OperationsV2 interface {
+	BulkInsert(*BulkInsertStruct) (*mgo.BulkResult, error)
+	BulkInsertCtx(context.Context, *BulkInsertStruct) (*mgo.BulkResult, error)
 	Insert(*InsertStruct) error
-	InsertAsync(*InsertStruct, *Callback)
+	InsertCtx(context.Context, *InsertStruct) error
+	InsertAsync(*InsertStruct, chan *Callback)
 	Update(*UpdateStruct) error
-	UpdateAsync(*UpdateStruct, *Callback)
+	UpdateCtx(context.Context, *UpdateStruct) error
+	UpdateAsync(*UpdateStruct, chan *Callback)
 	Upsert(*UpsertStruct) (*mgo.ChangeInfo, error)
-	UpsertAsync(*UpsertStruct, *Callback)
+	UpsertCtx(context.Context, *UpsertStruct) (*mgo.ChangeInfo, error)
+	UpsertAsync(*UpsertStruct, chan *Callback)
 	UpdateOne(UpdateOneStruct) error
+	UpdateOneCtx(context.Context, UpdateOneStruct) error
 	UpdateAll(UpdateAllStruct) (*mgo.ChangeInfo, error)
-	UpdateAllAsync(UpdateAllStruct, *Callback)
+	UpdateAllCtx(context.Context, UpdateAllStruct) (*mgo.ChangeInfo, error)
+	UpdateAllAsync(UpdateAllStruct, chan *Callback)
 	UpsertAll(*UpsertAllStruct) (*mgo.ChangeInfo, error)
-	UpsertAllAsync(*UpsertAllStruct, *Callback)
+	UpsertAllCtx(context.Context, *UpsertAllStruct) (*mgo.ChangeInfo, error)
+	UpsertAllAsync(*UpsertAllStruct, chan *Callback)
 	FindByID(*FindByIDStruct) (interface{}, error)
-	FindByIDAsync(*FindByIDStruct, *Callback)
+	FindByIDCtx(context.Context, *FindByIDStruct) (interface{}, error)
+	FindByIDAsync(*FindByIDStruct, chan *Callback)
 	Find(*FindStruct) ([]interface{}, error)
-	FindAsync(*FindStruct, *Callback)
+	FindCtx(context.Context, *FindStruct) ([]interface{}, error)
+	FindAsync(*FindStruct, chan *Callback)
 	FindAll(*FindAllStruct) ([]interface{}, error)
-	FindAllAsync(*FindAllStruct, *Callback)
+	FindAllCtx(context.Context, *FindAllStruct) ([]interface{}, error)
+	FindAllAsync(*FindAllStruct, chan *Callback)
 	Remove(*RemoveStruct) error
-	RemoveAsync(*RemoveStruct, *Callback)
+	RemoveCtx(context.Context, *RemoveStruct) error
+	RemoveAsync(*RemoveStruct, chan *Callback)
 	RemoveAll(*RemoveAllStruct) (*mgo.ChangeInfo, error)
-	RemoveAllAsync(*RemoveAllStruct, *Callback)
+	RemoveAllCtx(context.Context, *RemoveAllStruct) (*mgo.ChangeInfo, error)
+	RemoveAllAsync(*RemoveAllStruct, chan *Callback)
+}
+
+// OperationsV3 is the set of document operations of a Connection, satisfied by *Connection and *Collection,
+// so callers can depend on it and swap in wrappers (i.e, instrumentation) or fakes
+// Every operation comes with a Ctx variant taking a context, the Async variants send a single Callback on the channel
+// V3 returns a *WriteResult from every write, V2 returned nothing but an error from some of them
+type OperationsV3 interface {
+	BulkInsert(*BulkInsertStruct) (*WriteResult, error)
+	BulkInsertCtx(context.Context, *BulkInsertStruct) (*WriteResult, error)
+	Insert(*InsertStruct) (*WriteResult, error)
+	InsertCtx(context.Context, *InsertStruct) (*WriteResult, error)
+	InsertAsync(*InsertStruct, chan *Callback)
+	Update(*UpdateStruct) (*WriteResult, error)
+	UpdateCtx(context.Context, *UpdateStruct) (*WriteResult, error)
+	UpdateAsync(*UpdateStruct, chan *Callback)
+	Upsert(*UpsertStruct) (*WriteResult, error)
+	UpsertCtx(context.Context, *UpsertStruct) (*WriteResult, error)
+	UpsertAsync(*UpsertStruct, chan *Callback)
+	UpdateOne(UpdateOneStruct) (*WriteResult, error)
+	UpdateOneCtx(context.Context, UpdateOneStruct) (*WriteResult, error)
+	UpdateAll(UpdateAllStruct) (*WriteResult, error)
+	UpdateAllCtx(context.Context, UpdateAllStruct) (*WriteResult, error)
+	UpdateAllAsync(UpdateAllStruct, chan *Callback)
+	UpsertAll(*UpsertAllStruct) (*WriteResult, error)
+	UpsertAllCtx(context.Context, *UpsertAllStruct) (*WriteResult, error)
+	UpsertAllAsync(*UpsertAllStruct, chan *Callback)
+	FindByID(*FindByIDStruct) (interface{}, error)
+	FindByIDCtx(context.Context, *FindByIDStruct) (interface{}, error)
+	FindByIDAsync(*FindByIDStruct, chan *Callback)
+	Find(*FindStruct) ([]interface{}, error)
+	FindCtx(context.Context, *FindStruct) ([]interface{}, error)
+	FindAsync(*FindStruct, chan *Callback)
+	FindAll(*FindAllStruct) ([]interface{}, error)
+	FindAllCtx(context.Context, *FindAllStruct) ([]interface{}, error)
+	FindAllAsync(*FindAllStruct, chan *Callback)
+	Remove(*RemoveStruct) (*WriteResult, error)
+	RemoveCtx(context.Context, *RemoveStruct) (*WriteResult, error)
+	RemoveAsync(*RemoveStruct, chan *Callback)
+	RemoveAll(*RemoveAllStruct) (*WriteResult, error)
+	RemoveAllCtx(context.Context, *RemoveAllStruct) (*WriteResult, error)
+	RemoveAllAsync(*RemoveAllStruct, chan *Callback)
 }
 
+// OperationsV4 adds to OperationsV3 the cursors, the pages, the counts, the aggregations and the FindOneAnd operations
+type OperationsV4 interface {
+	OperationsV3
+	FindCursor(*FindStruct) (*Cursor, error)
+	FindCursorCtx(context.Context, *FindStruct) (*Cursor, error)
+	FindStream(context.Context, *FindStruct, chan *Callback)
+	FindPage(*PageStruct) (*Page, error)
+	FindPageCtx(context.Context, *PageStruct) (*Page, error)
+	FindPageAsync(*PageStruct, chan *Callback)
+	Count(*CountStruct) (int, error)
+	CountCtx(context.Context, *CountStruct) (int, error)
+	CountAsync(*CountStruct, chan *Callback)
+	Distinct(*DistinctStruct) ([]interface{}, error)
+	DistinctCtx(context.Context, *DistinctStruct) ([]interface{}, error)
+	DistinctAsync(*DistinctStruct, chan *Callback)
+	Exists(*ExistsStruct) (bool, error)
+	ExistsCtx(context.Context, *ExistsStruct) (bool, error)
+	ExistsAsync(*ExistsStruct, chan *Callback)
+	Aggregate(*AggregateStruct) ([]interface{}, error)
+	AggregateCtx(context.Context, *AggregateStruct) ([]interface{}, error)
+	AggregateAsync(*AggregateStruct, chan *Callback)
+	AggregateCursor(*AggregateStruct) (*Cursor, error)
+	AggregateCursorCtx(context.Context, *AggregateStruct) (*Cursor, error)
+	AggregateStream(context.Context, *AggregateStruct, chan *Callback)
+	FindOneAndUpdate(*FindOneAndUpdateStruct) (interface{}, error)
+	FindOneAndUpdateCtx(context.Context, *FindOneAndUpdateStruct) (interface{}, error)
+	FindOneAndUpdateAsync(*FindOneAndUpdateStruct, chan *Callback)
+	FindOneAndReplace(*FindOneAndReplaceStruct) (interface{}, error)
+	FindOneAndReplaceCtx(context.Context, *FindOneAndReplaceStruct) (interface{}, error)
+	FindOneAndReplaceAsync(*FindOneAndReplaceStruct, chan *Callback)
+	FindOneAndDelete(*FindOneAndDeleteStruct) (interface{}, error)
+	FindOneAndDeleteCtx(context.Context, *FindOneAndDeleteStruct) (interface{}, error)
+	FindOneAndDeleteAsync(*FindOneAndDeleteStruct, chan *Callback)
+}
+
+// Operations is the current version of the document operations
+type Operations = OperationsV4
+
+//compile time checks of the implementations
+var (
+	_ OperationsV2 = (*operationsV2)(nil)
+	_ OperationsV3 = (*Connection)(nil)
+	_ OperationsV3 = (*Collection)(nil)
+	_ OperationsV4 = (*Connection)(nil)
+	_ OperationsV4 = (*Collection)(nil)
+)
+
 type Connection struct {
-	Database    string                     //database name
-	DialInfo    *mgo.DialInfo              // connection info
-	Session     *mgo.Session               //session info
-	Collections map[string]*mgo.Collection //all collections
-	Collection  string                     //collection name
+	Database    string                 //database name
+	Options     *options.ClientOptions //options the client was connected with
+	Client      *mongo.Client          //client of the MongoDB driver, nil for the other drivers
+	Collections map[string]*Collection //cache of the handles returned by C
+	Collection  string                 //collection name used by the Connection level operations, prefer C for shared connections
+	Store       Store                  //storage of the drivers other than MongoDB, nil for MongoDB
+
+	collectionsMu sync.Mutex //guards Collections
+	monitor       *monitor   //background health check started by Monitor, nil when stopped
+	monitorMu     sync.Mutex //guards monitor
+}
+
+// WriteResult reports the outcome of a write operation
+type WriteResult struct {
+	Matched     int           //records matched by the query
+	Modified    int           //records changed by an update, an update leaving a record as it was doesn't count
+	Removed     int           //records removed
+	UpsertedId  interface{}   //_id of the record inserted by an upsert, nil when an existing record was updated
+	InsertedIds []interface{} //_id of the inserted records in the given order, ObjectIds generated for the records without one included
+}
+
+// MonitorConfig describes the background health check started by Connection.Monitor
+// The callbacks run in order on a goroutine of their own, so they may call StopMonitor or Close,
+// the ones still queued when the monitor stops are dropped
+type MonitorConfig struct {
+	Interval    time.Duration //time between two checks of a healthy connection, DefaultMonitorInterval when 0
+	PingTimeout time.Duration //timeout of a single check, DefaultPingTimeout when 0
+	MinBackoff  time.Duration //wait after the first failed check, doubled after each failure, DefaultMinBackoff when 0
+	MaxBackoff  time.Duration //cap of the wait between failed checks, DefaultMaxBackoff when 0
+
+	OnConnected    func(conn *Connection)            //called once the first check succeeds
+	OnDisconnected func(conn *Connection, err error) //called when a check fails, once per outage
+	OnReconnected  func(conn *Connection)            //called when a check succeeds after an outage
 }
 
 type Config struct {
-	Uri            string
-	DbType         string
-	Hosts          string //connection url i.e, localhost:27017
-	Database       string //database name
-	ReplicaSetName string //database name
-	Username       string //username
-	Password       string //password
-	AuthDatabase   string //auth db
-	Direct         bool
+	Uri            string   `json:"uri" yaml:"uri"`
+	DbType         string   `json:"dbType" yaml:"dbType"`
+	Hosts          string   `json:"hosts" yaml:"hosts"`               //connection url i.e, localhost:27017, a comma separated seed list is accepted too
+	Addrs          []string `json:"addrs" yaml:"addrs"`               //seed list i.e, ["mongo1:27017", "mongo2:27017"], takes precedence over Hosts
+	Database       string   `json:"database" yaml:"database"`         //database name
+	ReplicaSetName string   `json:"replicaSet" yaml:"replicaSet"`     //database name
+	Username       string   `json:"username" yaml:"username"`         //username
+	Password       string   `json:"password" yaml:"password"`         //password
+	AuthDatabase   string   `json:"authDatabase" yaml:"authDatabase"` //auth db
+	Direct         bool     `json:"direct" yaml:"direct"`
+
+	AuthMechanism   string `json:"authMechanism" yaml:"authMechanism"`     //SCRAMSHA1, SCRAMSHA256, MONGODBCR, PLAIN, X509 or GSSAPI, negotiated with the server when empty
+	AuthService     string `json:"authService" yaml:"authService"`         //GSSAPI service name, driver default "mongodb" when empty
+	AuthServiceHost string `json:"authServiceHost" yaml:"authServiceHost"` //GSSAPI host name, the server address when empty
+
+	TLS *TLSConfig `json:"tls" yaml:"tls"` //encryption of the connection, plain TCP when nil or not Enabled
+
+	PoolLimit     int           `json:"poolLimit" yaml:"poolLimit"`         //max sockets per server, DefaultPoolLimit when 0
+	DialTimeout   time.Duration `json:"dialTimeout" yaml:"dialTimeout"`     //timeout for establishing the connection, DefaultDialTimeout when 0
+	SocketTimeout time.Duration `json:"socketTimeout" yaml:"socketTimeout"` //timeout of a single round trip, driver default (1 minute) when 0
+	SyncTimeout   time.Duration `json:"syncTimeout" yaml:"syncTimeout"`     //timeout for finding a usable server, driver default (1 minute) when 0
+	WriteConcern  *WriteConcern `json:"writeConcern" yaml:"writeConcern"`   //acknowledgement of writes, majority when nil
+	Consistency   string        `json:"consistency" yaml:"consistency"`     //STRONG, MONOTONIC, EVENTUAL or a read preference (PRIMARY, NEAREST, etc), STRONG when empty
+}
+
+// TLSConfig describes the TLS options of a connection
+type TLSConfig struct {
+	Enabled            bool   `json:"enabled" yaml:"enabled"`                       //connect over TLS
+	CAFile             string `json:"caFile" yaml:"caFile"`                         //PEM bundle of the authorities trusted for the server certificate, system pool when empty
+	CertFile           string `json:"certFile" yaml:"certFile"`                     //PEM client certificate, required by X509 authentication
+	KeyFile            string `json:"keyFile" yaml:"keyFile"`                       //PEM key of the client certificate, CertFile is read when empty (combined PEM)
+	ServerName         string `json:"serverName" yaml:"serverName"`                 //name expected in the server certificate, the host of each address when empty
+	InsecureSkipVerify bool   `json:"insecureSkipVerify" yaml:"insecureSkipVerify"` //skip the verification of the server certificate, only for development
+}
+
+// WriteConcern describes how writes are acknowledged by the server
+type WriteConcern struct {
+	W        int           `json:"w" yaml:"w"`               //number of servers which must acknowledge the write
+	WMode    string        `json:"wMode" yaml:"wMode"`       //tag set or "majority", takes precedence over W
+	J        bool          `json:"j" yaml:"j"`               //wait for the write to reach the journal
+	WTimeout time.Duration `json:"wTimeout" yaml:"wTimeout"` //give up waiting for W / WMode after this long, 0 waits forever
 }
 
 type BulkInsertStruct struct {
@@ -69,12 +238,12 @@ type InsertStruct struct {
 }
 
 type UpdateStruct struct {
-	Id   string
+	Id   interface{} //Hexadecimal ObjectId, bson.ObjectId, StringID or any other _id value
 	Data interface{}
 }
 
 type UpsertStruct struct {
-	Id   string
+	Id   interface{} //Hexadecimal ObjectId, bson.ObjectId, StringID or any other _id value
 	Data interface{}
 }
 
@@ -94,18 +263,98 @@ type UpsertAllStruct struct {
 }
 
 type FindByIDStruct struct {
-	Id     string
+	Id     interface{} //Hexadecimal ObjectId, bson.ObjectId, StringID or any other _id value
 	Fields bson.M
 }
 
 type FindStruct struct {
 	Query   bson.M
-	Options map[string]int
+	Options map[string]int //Deprecated: use Skip and Limit, "limit", "skip" and "isSkip" fill them when they're 0
 	Fields  bson.M
+	FindOptions
+}
+
+type PageStruct struct {
+	Query  bson.M
+	Fields bson.M
+	Sort   string //key of the pages, "-" sorts descending i.e, "-createdAt", _id when empty
+	Limit  int    //records per page, DefaultPageSize when 0
+	Token  string //NextToken of the previous page, empty for the first page
+}
+
+type AggregateStruct struct {
+	Pipeline     Pipeline      //stages in order, build them with the Pipeline methods or give them as []bson.M
+	AllowDiskUse bool          //let the stages over the memory limit of the server write temporary files
+	BatchSize    int           //records per round trip, 0 lets the server choose
+	MaxTime      time.Duration //time the server may spend on the pipeline, 0 for the deadline of the context
+}
+
+type CountStruct struct {
+	Query     bson.M
+	Estimated bool //read the count from the collection metadata instead of scanning, needs an empty Query
+}
+
+type DistinctStruct struct {
+	Field string //dotted path of the field i.e, "address.city"
+	Query bson.M
+}
+
+type ExistsStruct struct {
+	Query bson.M
 }
 
 type FindAllStruct struct {
 	Fields bson.M
+	FindOptions
+}
+
+// FindOptions tunes Find and FindAll, the zero value returns every record in the natural order
+type FindOptions struct {
+	Sort      []string      //fields to sort on in order, "-" sorts descending i.e, []string{"lastName", "-age"}
+	Skip      int           //records to skip
+	Limit     int           //maximum number of records, 0 for all
+	Hint      interface{}   //index to use, its name or its keys i.e, bson.D{{Name: "age", Value: 1}}
+	BatchSize int           //records per round trip, 0 lets the server choose
+	MaxTime   time.Duration //time the server may spend on the query, 0 for the deadline of the context
+	Collation *Collation    //language rules used to compare strings, ignored by the drivers other than MongoDB
+	Snapshot  bool          //walk the _id index so a record moved by a concurrent write isn't returned twice, ignored with a Hint
+}
+
+// Collation describes the language rules used to compare strings, see the collation document of MongoDB
+type Collation struct {
+	Locale          string //i.e, "en", "fr_CA", "simple" for a binary comparison
+	CaseLevel       bool   //compare the case at strength 1 or 2
+	CaseFirst       string //"upper", "lower" or "off"
+	Strength        int    //1 compares base characters only, 2 adds accents, 3 (default) adds case
+	NumericOrdering bool   //compare numeric strings as numbers, "10" after "9"
+	Alternate       string //"non-ignorable" or "shifted" to ignore spaces and punctuation
+	MaxVariable     string //"punct" or "space", characters ignored when Alternate is "shifted"
+	Normalization   bool   //check the text requires normalization and normalize it
+	Backwards       bool   //sort the strings with diacritics from the back of the string, as in French
+}
+
+type FindOneAndUpdateStruct struct {
+	Query     bson.M
+	Data      interface{} //update operators i.e, bson.M{"$set": bson.M{"status": "claimed"}}
+	Sort      []string    //picks the record when several match, "-" sorts descending i.e, []string{"-priority"}
+	Fields    bson.M      //fields of the returned record
+	Upsert    bool        //insert a record built from Query and Data when none matches
+	ReturnNew bool        //return the record as updated instead of as it was
+}
+
+type FindOneAndReplaceStruct struct {
+	Query     bson.M
+	Data      interface{} //the new record, without update operators, it keeps the _id of the replaced one
+	Sort      []string    //picks the record when several match, "-" sorts descending
+	Fields    bson.M      //fields of the returned record
+	Upsert    bool        //insert Data when no record matches
+	ReturnNew bool        //return the new record instead of the replaced one
+}
+
+type FindOneAndDeleteStruct struct {
+	Query  bson.M
+	Sort   []string //picks the record when several match, "-" sorts descending
+	Fields bson.M   //fields of the returned record
 }
 
 type RemoveStruct struct {
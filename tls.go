@@ -0,0 +1,44 @@
+package gomongo
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+)
+
+// tlsConfig : Function loads the certificates of config into a *tls.Config
+func (config *TLSConfig) tlsConfig() (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		ServerName:         config.ServerName,
+		InsecureSkipVerify: config.InsecureSkipVerify,
+	}
+
+	if len(config.CAFile) > 0 {
+		pem, err := os.ReadFile(config.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("%w: reading CA file: %v", ErrorInvalidConfig, err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("%w: no certificate found in CA file %s", ErrorInvalidConfig, config.CAFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if len(config.CertFile) > 0 {
+		keyFile := config.KeyFile
+		if len(keyFile) == 0 {
+			keyFile = config.CertFile
+		}
+		cert, err := tls.LoadX509KeyPair(config.CertFile, keyFile)
+		if err != nil {
+			return nil, fmt.Errorf("%w: loading client certificate: %v", ErrorInvalidConfig, err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	} else if len(config.KeyFile) > 0 {
+		return nil, fmt.Errorf("%w: KeyFile given without CertFile", ErrorInvalidConfig)
+	}
+
+	return tlsConfig, nil
+}
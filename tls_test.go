@@ -0,0 +1,88 @@
+package gomongo
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+//writes a self signed certificate and its key into dir
+func writeTestCertificate(t *testing.T, dir string) (string, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.Nil(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "client", OrganizationalUnit: []string{"gomongo"}},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	assert.Nil(t, err)
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	assert.Nil(t, err)
+
+	certFile := filepath.Join(dir, "client.crt")
+	keyFile := filepath.Join(dir, "client.key")
+	assert.Nil(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600))
+	assert.Nil(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}), 0600))
+	return certFile, keyFile
+}
+
+func TestTLSConfig(t *testing.T) {
+	certFile, keyFile := writeTestCertificate(t, t.TempDir())
+
+	config := &TLSConfig{Enabled: true, CAFile: certFile, CertFile: certFile, KeyFile: keyFile, ServerName: "mongo.internal"}
+	tlsConfig, err := config.tlsConfig()
+	assert.Nil(t, err)
+	assert.NotNil(t, tlsConfig.RootCAs)
+	assert.Equal(t, 1, len(tlsConfig.Certificates))
+	assert.Equal(t, "mongo.internal", tlsConfig.ServerName)
+
+	info, err := resolvedOptions(&Config{Uri: "mongodb://localhost:27017/golang_test", TLS: config})
+	assert.Nil(t, err)
+	assert.NotNil(t, info.TLSConfig)
+
+	info, err = clientOptions(&Config{Hosts: TestDBHosts, Database: TestDatabase, TLS: config})
+	assert.Nil(t, err)
+	assert.NotNil(t, info.TLSConfig)
+
+	info, err = clientOptions(&Config{Hosts: TestDBHosts, Database: TestDatabase, TLS: &TLSConfig{CAFile: certFile}})
+	assert.Nil(t, err)
+	assert.Nil(t, info.TLSConfig)
+}
+
+func TestInvalidTLSConfig(t *testing.T) {
+	dir := t.TempDir()
+	certFile, _ := writeTestCertificate(t, dir)
+	notPem := filepath.Join(dir, "not.pem")
+	assert.Nil(t, os.WriteFile(notPem, []byte("not a certificate"), 0600))
+
+	configs := []*TLSConfig{
+		{Enabled: true, CAFile: filepath.Join(dir, "missing.pem")},
+		{Enabled: true, CAFile: notPem},
+		{Enabled: true, CertFile: certFile, KeyFile: notPem},
+		{Enabled: true, KeyFile: certFile},
+	}
+	for _, config := range configs {
+		_, err := clientOptions(&Config{Hosts: TestDBHosts, TLS: config})
+		assert.True(t, errors.Is(err, ErrorInvalidConfig), "%+v", config)
+	}
+
+	_, err := clientOptions(&Config{Hosts: TestDBHosts, Username: "CN=client,OU=gomongo", AuthMechanism: X509, TLS: &TLSConfig{Enabled: true}})
+	assert.True(t, errors.Is(err, ErrorInvalidConfig))
+}